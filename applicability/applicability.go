@@ -0,0 +1,193 @@
+// Package applicability implements the CPE Applicability Language, as described in NIST IR 7698.
+package applicability
+
+import (
+	"github.com/knqyf263/go-cpe/common"
+	"github.com/knqyf263/go-cpe/matching"
+)
+
+// Result is enumeration for the results of evaluating a test.
+type Result int
+
+const (
+	// FALSE : false
+	FALSE Result = iota
+	// TRUE : true
+	TRUE
+	// ERROR : the test could not be evaluated
+	ERROR
+)
+
+// String returns string representation of the Result
+func (r Result) String() string {
+	switch r {
+	case TRUE:
+		return "TRUE"
+	case FALSE:
+		return "FALSE"
+	}
+	return "ERROR"
+}
+
+// Operator is the logical operator of a LogicalTest.
+type Operator string
+
+const (
+	// AND : every child must be TRUE
+	AND Operator = "AND"
+	// OR : at least one child must be TRUE
+	OR Operator = "OR"
+)
+
+// PlatformSpecification is a collection of platforms.
+type PlatformSpecification struct {
+	Platforms []Platform
+}
+
+// Platform is a named applicability statement.
+type Platform struct {
+	ID      string
+	Titles  []string
+	Remarks []string
+	Test    LogicalTest
+}
+
+// LogicalTest combines its fact refs, check fact refs and nested tests with an operator,
+// and optionally negates the result.
+type LogicalTest struct {
+	Operator      Operator
+	Negate        bool
+	Tests         []LogicalTest
+	FactRefs      []FactRef
+	CheckFactRefs []CheckFactRef
+}
+
+// FactRef is TRUE if its name matches one of the known platforms.
+type FactRef struct {
+	Name        common.WellFormedName
+	Description string
+}
+
+// CheckFactRef refers to a check, such as an OVAL definition,
+// that determines if a platform is present.
+type CheckFactRef struct {
+	System      string
+	Href        string
+	IDRef       string
+	Description string
+}
+
+// CheckEvaluator evaluates check fact refs.
+type CheckEvaluator interface {
+	EvaluateCheck(check CheckFactRef) Result
+}
+
+// CheckEvaluatorFunc is an adapter to allow the use of ordinary functions as CheckEvaluator.
+type CheckEvaluatorFunc func(check CheckFactRef) Result
+
+// EvaluateCheck calls f(check)
+func (f CheckEvaluatorFunc) EvaluateCheck(check CheckFactRef) Result {
+	return f(check)
+}
+
+// Evaluate evaluates the fact ref against the known platforms.
+// @param known WFNs of the platforms known to be present
+// @return TRUE if the fact ref name is a superset of or equal to a known platform,
+// ERROR if the fact ref has no name, FALSE otherwise
+func (f FactRef) Evaluate(known []common.WellFormedName) Result {
+	if f.Name == nil {
+		return ERROR
+	}
+	for _, k := range known {
+		if matching.IsSuperset(f.Name, k) {
+			return TRUE
+		}
+	}
+	return FALSE
+}
+
+// Evaluate evaluates the platform test against the known platforms.
+// Check fact refs evaluate to ERROR; use EvaluateWithChecks to evaluate them.
+func (p Platform) Evaluate(known []common.WellFormedName) Result {
+	return p.Test.Evaluate(known)
+}
+
+// EvaluateWithChecks evaluates the platform test, using checks to evaluate check fact refs.
+func (p Platform) EvaluateWithChecks(known []common.WellFormedName, checks CheckEvaluator) Result {
+	return p.Test.EvaluateWithChecks(known, checks)
+}
+
+// Evaluate evaluates the test against the known platforms.
+// Check fact refs evaluate to ERROR; use EvaluateWithChecks to evaluate them.
+// @param known WFNs of the platforms known to be present
+// @return TRUE, FALSE or ERROR
+func (t LogicalTest) Evaluate(known []common.WellFormedName) Result {
+	return t.EvaluateWithChecks(known, nil)
+}
+
+// EvaluateWithChecks evaluates the test against the known platforms.
+// AND is FALSE if any child is FALSE, otherwise ERROR if any child is ERROR, otherwise TRUE.
+// OR is TRUE if any child is TRUE, otherwise ERROR if any child is ERROR, otherwise FALSE.
+// Negation swaps TRUE and FALSE and leaves ERROR unchanged.
+// A test without children, or with an unknown operator, is ERROR.
+// @param known WFNs of the platforms known to be present
+// @param checks CheckEvaluator, or nil to evaluate check fact refs to ERROR
+// @return TRUE, FALSE or ERROR
+func (t LogicalTest) EvaluateWithChecks(known []common.WellFormedName, checks CheckEvaluator) Result {
+	var results []Result
+	for _, f := range t.FactRefs {
+		results = append(results, f.Evaluate(known))
+	}
+	for _, c := range t.CheckFactRefs {
+		if checks == nil {
+			results = append(results, ERROR)
+		} else {
+			results = append(results, checks.EvaluateCheck(c))
+		}
+	}
+	for _, child := range t.Tests {
+		results = append(results, child.EvaluateWithChecks(known, checks))
+	}
+	if len(results) == 0 {
+		return ERROR
+	}
+
+	var result Result
+	switch t.Operator {
+	case AND:
+		result = combine(results, FALSE, TRUE)
+	case OR:
+		result = combine(results, TRUE, FALSE)
+	default:
+		return ERROR
+	}
+	if t.Negate {
+		return negate(result)
+	}
+	return result
+}
+
+// combine returns dominant if any result is dominant, ERROR if any result
+// is ERROR, and fallback otherwise.
+func combine(results []Result, dominant, fallback Result) Result {
+	result := fallback
+	for _, r := range results {
+		if r == dominant {
+			return dominant
+		}
+		if r != TRUE && r != FALSE {
+			result = ERROR
+		}
+	}
+	return result
+}
+
+func negate(r Result) Result {
+	switch r {
+	case TRUE:
+		return FALSE
+	case FALSE:
+		return TRUE
+	}
+	return ERROR
+}
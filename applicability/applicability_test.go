@@ -0,0 +1,84 @@
+package applicability
+
+import (
+	"testing"
+
+	"github.com/knqyf263/go-cpe/common"
+	"github.com/knqyf263/go-cpe/naming"
+)
+
+func mustUnbindFS(t *testing.T, fs string) common.WellFormedName {
+	wfn, err := naming.UnbindFS(fs)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s, FS: %s", err, fs)
+	}
+	return wfn
+}
+
+func TestLogicalTestEvaluate(t *testing.T) {
+	windows := FactRef{Name: mustUnbindFS(t, "cpe:2.3:o:microsoft:windows_7:*:*:*:*:*:*:*:*")}
+	reader := FactRef{Name: mustUnbindFS(t, "cpe:2.3:a:adobe:reader:9.*:*:*:*:*:*:*:*")}
+	linux := FactRef{Name: mustUnbindFS(t, "cpe:2.3:o:linux:linux_kernel:*:*:*:*:*:*:*:*")}
+	check := CheckFactRef{System: "http://oval.mitre.org/XMLSchema/oval-definitions-5", IDRef: "oval:x:def:1"}
+
+	known := []common.WellFormedName{
+		mustUnbindFS(t, "cpe:2.3:o:microsoft:windows_7:-:sp1:*:*:*:*:*:*"),
+		mustUnbindFS(t, "cpe:2.3:a:adobe:reader:9.3.2:*:*:*:*:*:*:*"),
+	}
+
+	vectors := []struct {
+		test     LogicalTest
+		checks   CheckEvaluator
+		expected Result
+	}{{
+		test:     LogicalTest{Operator: AND, FactRefs: []FactRef{windows, reader}},
+		expected: TRUE,
+	}, {
+		test:     LogicalTest{Operator: AND, FactRefs: []FactRef{windows, linux}},
+		expected: FALSE,
+	}, {
+		test:     LogicalTest{Operator: OR, FactRefs: []FactRef{windows, linux}},
+		expected: TRUE,
+	}, {
+		test:     LogicalTest{Operator: OR, Negate: true, FactRefs: []FactRef{windows, linux}},
+		expected: FALSE,
+	}, {
+		test: LogicalTest{Operator: AND, FactRefs: []FactRef{reader}, Tests: []LogicalTest{{
+			Operator: OR, Negate: true, FactRefs: []FactRef{linux},
+		}}},
+		expected: TRUE,
+	}, {
+		test:     LogicalTest{Operator: AND, FactRefs: []FactRef{windows}, CheckFactRefs: []CheckFactRef{check}},
+		expected: ERROR,
+	}, {
+		test:     LogicalTest{Operator: AND, FactRefs: []FactRef{linux}, CheckFactRefs: []CheckFactRef{check}},
+		expected: FALSE,
+	}, {
+		test:     LogicalTest{Operator: OR, FactRefs: []FactRef{windows}, CheckFactRefs: []CheckFactRef{check}},
+		expected: TRUE,
+	}, {
+		test:     LogicalTest{Operator: OR, Negate: true, FactRefs: []FactRef{linux}, CheckFactRefs: []CheckFactRef{check}},
+		expected: ERROR,
+	}, {
+		test:     LogicalTest{Operator: AND, FactRefs: []FactRef{windows}, CheckFactRefs: []CheckFactRef{check}},
+		checks:   CheckEvaluatorFunc(func(CheckFactRef) Result { return TRUE }),
+		expected: TRUE,
+	}, {
+		test:     LogicalTest{Operator: AND},
+		expected: ERROR,
+	}, {
+		test:     LogicalTest{Operator: "XOR", FactRefs: []FactRef{windows}},
+		expected: ERROR,
+	}, {
+		test:     LogicalTest{Operator: AND, FactRefs: []FactRef{{}}},
+		expected: ERROR,
+	},
+	}
+
+	for i, v := range vectors {
+		actual := v.test.EvaluateWithChecks(known, v.checks)
+		if actual != v.expected {
+			t.Errorf("test %d, Result: got %v, want %v", i, actual, v.expected)
+		}
+	}
+}
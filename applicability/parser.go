@@ -0,0 +1,100 @@
+package applicability
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"github.com/knqyf263/go-cpe/naming"
+	"github.com/pkg/errors"
+)
+
+// Parse parses a CPE 2.3 platform specification.
+// Fact ref names are formatted strings and are unbound by naming.UnbindFS.
+// @param r XML platform specification
+// @return PlatformSpecification, or an error if the XML or any name is invalid
+func Parse(r io.Reader) (*PlatformSpecification, error) {
+	var x xmlPlatformSpecification
+	if err := xml.NewDecoder(r).Decode(&x); err != nil {
+		return nil, errors.Wrap(err, "Failed to decode platform specification")
+	}
+	spec := &PlatformSpecification{}
+	for _, p := range x.Platforms {
+		test, err := p.Test.convert()
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to parse platform %s", p.ID)
+		}
+		platform := Platform{ID: p.ID, Test: test}
+		for _, title := range p.Titles {
+			platform.Titles = append(platform.Titles, strings.TrimSpace(title))
+		}
+		for _, remark := range p.Remarks {
+			platform.Remarks = append(platform.Remarks, strings.TrimSpace(remark))
+		}
+		spec.Platforms = append(spec.Platforms, platform)
+	}
+	return spec, nil
+}
+
+type xmlPlatformSpecification struct {
+	Platforms []xmlPlatform `xml:"platform"`
+}
+
+type xmlPlatform struct {
+	ID      string         `xml:"id,attr"`
+	Titles  []string       `xml:"title"`
+	Remarks []string       `xml:"remark"`
+	Test    xmlLogicalTest `xml:"logical-test"`
+}
+
+type xmlLogicalTest struct {
+	Operator      string            `xml:"operator,attr"`
+	Negate        string            `xml:"negate,attr"`
+	Tests         []xmlLogicalTest  `xml:"logical-test"`
+	FactRefs      []xmlFactRef      `xml:"fact-ref"`
+	CheckFactRefs []xmlCheckFactRef `xml:"check-fact-ref"`
+}
+
+type xmlFactRef struct {
+	Name        string `xml:"name,attr"`
+	Description string `xml:"description,attr"`
+}
+
+type xmlCheckFactRef struct {
+	System      string `xml:"system,attr"`
+	Href        string `xml:"href,attr"`
+	IDRef       string `xml:"id-ref,attr"`
+	Description string `xml:"description,attr"`
+}
+
+func (x xmlLogicalTest) convert() (t LogicalTest, err error) {
+	t.Operator = Operator(strings.ToUpper(x.Operator))
+	if t.Operator != AND && t.Operator != OR {
+		return LogicalTest{}, errors.Errorf("unknown operator: %s", x.Operator)
+	}
+	switch strings.ToLower(x.Negate) {
+	case "", "false", "0":
+	case "true", "1":
+		t.Negate = true
+	default:
+		return LogicalTest{}, errors.Errorf("invalid negate: %s", x.Negate)
+	}
+	for _, f := range x.FactRefs {
+		wfn, err := naming.UnbindFS(f.Name)
+		if err != nil {
+			return LogicalTest{}, errors.Wrapf(err, "Failed to unbind %s", f.Name)
+		}
+		t.FactRefs = append(t.FactRefs, FactRef{Name: wfn, Description: f.Description})
+	}
+	for _, c := range x.CheckFactRefs {
+		t.CheckFactRefs = append(t.CheckFactRefs, CheckFactRef(c))
+	}
+	for _, child := range x.Tests {
+		test, err := child.convert()
+		if err != nil {
+			return LogicalTest{}, err
+		}
+		t.Tests = append(t.Tests, test)
+	}
+	return t, nil
+}
@@ -0,0 +1,69 @@
+package applicability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/knqyf263/go-cpe/common"
+)
+
+func TestParse(t *testing.T) {
+	input := `<?xml version="1.0" encoding="UTF-8"?>
+<cpe:platform-specification xmlns:cpe="http://cpe.mitre.org/language/2.0">
+  <cpe:platform id="123">
+    <cpe:title>Microsoft Windows 7 with Adobe Reader 9</cpe:title>
+    <cpe:logical-test operator="AND" negate="FALSE">
+      <cpe:logical-test operator="OR" negate="FALSE">
+        <cpe:fact-ref name="cpe:2.3:o:microsoft:windows_7:*:*:*:*:*:*:*:*"/>
+        <cpe:fact-ref name="cpe:2.3:o:microsoft:windows_8:*:*:*:*:*:*:*:*"/>
+      </cpe:logical-test>
+      <cpe:logical-test operator="OR" negate="TRUE">
+        <cpe:fact-ref name="cpe:2.3:a:adobe:reader:9.*:*:*:*:*:*:*:*"/>
+      </cpe:logical-test>
+      <cpe:check-fact-ref system="http://oval.mitre.org/XMLSchema/oval-definitions-5" href="oval.xml" id-ref="oval:x:def:1"/>
+    </cpe:logical-test>
+  </cpe:platform>
+</cpe:platform-specification>`
+
+	spec, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(spec.Platforms) != 1 {
+		t.Fatalf("Platforms: got %d, want 1", len(spec.Platforms))
+	}
+	p := spec.Platforms[0]
+	if p.ID != "123" || len(p.Titles) != 1 {
+		t.Errorf("Platform: got %v", p)
+	}
+	if p.Test.Operator != AND || p.Test.Negate || len(p.Test.Tests) != 2 || len(p.Test.CheckFactRefs) != 1 {
+		t.Errorf("Test: got %v", p.Test)
+	}
+	if !p.Test.Tests[1].Negate || p.Test.Tests[0].FactRefs[1].Name.Get(common.AttributeProduct) != "windows_8" {
+		t.Errorf("Tests: got %v", p.Test.Tests)
+	}
+	if p.Test.CheckFactRefs[0].IDRef != "oval:x:def:1" {
+		t.Errorf("CheckFactRefs: got %v", p.Test.CheckFactRefs)
+	}
+
+	known := []common.WellFormedName{mustUnbindFS(t, "cpe:2.3:o:microsoft:windows_8:-:*:*:*:*:*:*:*")}
+	checks := CheckEvaluatorFunc(func(CheckFactRef) Result { return TRUE })
+	if r := p.EvaluateWithChecks(known, checks); r != TRUE {
+		t.Errorf("Evaluate: got %v, want %v", r, TRUE)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	vectors := []string{
+		`<platform-specification><platform>`,
+		`<platform-specification><platform><logical-test operator="XOR"/></platform></platform-specification>`,
+		`<platform-specification><platform><logical-test operator="AND" negate="maybe"/></platform></platform-specification>`,
+		`<platform-specification><platform><logical-test operator="AND"><fact-ref name="cpe:/a:foo"/></logical-test></platform></platform-specification>`,
+	}
+
+	for i, v := range vectors {
+		if _, err := Parse(strings.NewReader(v)); err == nil {
+			t.Errorf("test %d, expected error", i)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package common
+
+import (
+	"github.com/pkg/errors"
+)
+
+// ValueKind is enumeration for the kinds of attribute values.
+type ValueKind int
+
+const (
+	// ValueAny : the logical value ANY
+	ValueAny ValueKind = iota
+	// ValueNA : the logical value NA
+	ValueNA
+	// ValueLiteral : a string without unquoted wildcards
+	ValueLiteral
+	// ValuePattern : a string containing unquoted wildcards
+	ValuePattern
+)
+
+// String returns string representation of the ValueKind
+func (k ValueKind) String() string {
+	switch k {
+	case ValueAny:
+		return "ANY"
+	case ValueNA:
+		return "NA"
+	case ValueLiteral:
+		return "LITERAL"
+	case ValuePattern:
+		return "PATTERN"
+	}
+	return "UNKNOWN"
+}
+
+// AttributeValue represents the value of a single WFN attribute.
+// It is either one of the logical values ANY or NA, a literal string,
+// or a string containing unquoted wildcards.
+// The zero value is ANY.
+type AttributeValue struct {
+	kind  ValueKind
+	value string
+}
+
+// NewAnyValue returns the logical value ANY
+func NewAnyValue() AttributeValue {
+	return AttributeValue{kind: ValueAny}
+}
+
+// NewNAValue returns the logical value NA
+func NewNAValue() AttributeValue {
+	return AttributeValue{kind: ValueNA}
+}
+
+// NewStringValue returns a literal or pattern value, depending on
+// whether s contains unquoted wildcards.
+// @param s string value in the quoted form used by WellFormedName
+func NewStringValue(s string) AttributeValue {
+	if ContainsWildcards(s) {
+		return AttributeValue{kind: ValuePattern, value: s}
+	}
+	return AttributeValue{kind: ValueLiteral, value: s}
+}
+
+// NewAttributeValue converts a value stored in a WellFormedName
+// (a string, a LogicalValue or nil) to an AttributeValue.
+// @param v value to convert
+// @return AttributeValue, or ErrIllegalAttribute for any other type
+func NewAttributeValue(v interface{}) (AttributeValue, error) {
+	switch t := v.(type) {
+	case nil:
+		return NewAnyValue(), nil
+	case LogicalValue:
+		if t.IsNA() {
+			return NewNAValue(), nil
+		}
+		return NewAnyValue(), nil
+	case string:
+		return NewStringValue(t), nil
+	case AttributeValue:
+		return t, nil
+	}
+	return AttributeValue{}, errors.Wrapf(ErrIllegalAttribute, "value must be a logical value or string: %T", v)
+}
+
+// Kind returns the kind of the value
+func (av AttributeValue) Kind() ValueKind {
+	return av.kind
+}
+
+// IsANY returns whether the value is the logical value ANY
+func (av AttributeValue) IsANY() bool {
+	return av.kind == ValueAny
+}
+
+// IsNA returns whether the value is the logical value NA
+func (av AttributeValue) IsNA() bool {
+	return av.kind == ValueNA
+}
+
+// IsLiteral returns whether the value is a string without unquoted wildcards
+func (av AttributeValue) IsLiteral() bool {
+	return av.kind == ValueLiteral
+}
+
+// IsPattern returns whether the value is a string with unquoted wildcards
+func (av AttributeValue) IsPattern() bool {
+	return av.kind == ValuePattern
+}
+
+// IsLogical returns whether the value is ANY or NA
+func (av AttributeValue) IsLogical() bool {
+	return av.kind == ValueAny || av.kind == ValueNA
+}
+
+// Value returns the string value, or "" for logical values
+func (av AttributeValue) Value() string {
+	return av.value
+}
+
+// Interface returns the value in the form stored by WellFormedName:
+// a LogicalValue for ANY and NA, a string otherwise.
+func (av AttributeValue) Interface() interface{} {
+	switch av.kind {
+	case ValueAny:
+		return LogicalValue{Any: true}
+	case ValueNA:
+		return LogicalValue{Na: true}
+	}
+	return av.value
+}
+
+// String returns "ANY" or "NA" for logical values, and the string value otherwise
+func (av AttributeValue) String() string {
+	if av.IsLogical() {
+		return av.kind.String()
+	}
+	return av.value
+}
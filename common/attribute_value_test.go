@@ -0,0 +1,93 @@
+package common
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestNewAttributeValue(t *testing.T) {
+	vectors := []struct {
+		v             interface{}
+		expectedKind  ValueKind
+		expectedValue string
+		wantErr       error
+	}{{
+		v:            nil,
+		expectedKind: ValueAny,
+	}, {
+		v:            any,
+		expectedKind: ValueAny,
+	}, {
+		v:            na,
+		expectedKind: ValueNA,
+	}, {
+		v:             "microsoft",
+		expectedKind:  ValueLiteral,
+		expectedValue: "microsoft",
+	}, {
+		v:             `8\.0\.*`,
+		expectedKind:  ValuePattern,
+		expectedValue: `8\.0\.*`,
+	}, {
+		v:             `sp\?`,
+		expectedKind:  ValueLiteral,
+		expectedValue: `sp\?`,
+	}, {
+		v:       1,
+		wantErr: ErrIllegalAttribute,
+	},
+	}
+
+	for i, v := range vectors {
+		actual, err := NewAttributeValue(v.v)
+		if errors.Cause(err) != v.wantErr {
+			t.Errorf("test %d, Error: got %v, want %v", i, errors.Cause(err), v.wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		if actual.Kind() != v.expectedKind {
+			t.Errorf("test %d, Kind: got %v, want %v", i, actual.Kind(), v.expectedKind)
+		}
+		if actual.Value() != v.expectedValue {
+			t.Errorf("test %d, Value: got %v, want %v", i, actual.Value(), v.expectedValue)
+		}
+	}
+}
+
+func TestAttributeValueInterface(t *testing.T) {
+	vectors := []struct {
+		av       AttributeValue
+		expected interface{}
+		str      string
+	}{{
+		av:       AttributeValue{},
+		expected: any,
+		str:      "ANY",
+	}, {
+		av:       NewNAValue(),
+		expected: na,
+		str:      "NA",
+	}, {
+		av:       NewStringValue("foo"),
+		expected: "foo",
+		str:      "foo",
+	}, {
+		av:       NewStringValue("foo*"),
+		expected: "foo*",
+		str:      "foo*",
+	},
+	}
+
+	for i, v := range vectors {
+		actual := v.av.Interface()
+		if !reflect.DeepEqual(actual, v.expected) {
+			t.Errorf("test %d, Result: %v, want %v", i, actual, v.expected)
+		}
+		if v.av.String() != v.str {
+			t.Errorf("test %d, String: got %v, want %v", i, v.av.String(), v.str)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+//go:build go1.18
+// +build go1.18
+
+package common
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func FuzzValidate(f *testing.F) {
+	f.Add(`cpe:2.3:a:microsoft:internet_explorer:8.0.6001:beta:*:sp2:*:*\::*:*`)
+	f.Add(`cpe:/a:microsoft:internet_explorer:8.0.6001:beta::sp2`)
+	f.Add(`internet_explorer\`)
+	f.Add(`??foo\?*`)
+	f.Fuzz(func(t *testing.T, s string) {
+		for _, err := range []error{ValidateURI(s), ValidateFS(s), ValidateStringValue(s)} {
+			if err != nil && errors.Cause(err) != ErrParse {
+				t.Fatalf("%q: got %v, want a parse error", s, err)
+			}
+		}
+		if ValidateStringValue(s) == nil {
+			if _, err := NewAttributeValue(s); err != nil {
+				t.Fatalf("NewAttributeValue(%q): %v", s, err)
+			}
+		}
+		ContainsWildcards(s)
+		ContainsQuestions(s)
+		LengthWithEscapeCharacters(s)
+		Unquote(s)
+		GetUnescapedColonIndex(s)
+		IndexNth(s, ":", 3)
+		IndexOf(s, ":", len(s)/2)
+		IsAlphanum(s)
+	})
+}
@@ -0,0 +1,264 @@
+package common
+
+import (
+	"strings"
+)
+
+// grandfatheredTags are the tags that RFC 5646 section 2.2.8 allows as a whole,
+// although they do not follow the langtag syntax
+var grandfatheredTags = []string{
+	// irregular
+	"en-gb-oed", "i-ami", "i-bnn", "i-default", "i-enochian", "i-hak", "i-klingon",
+	"i-lux", "i-mingo", "i-navajo", "i-pwn", "i-tao", "i-tay", "i-tsu",
+	"sgn-be-fr", "sgn-be-nl", "sgn-ch-de",
+	// regular
+	"art-lojban", "cel-gaulish", "no-bok", "no-nyn", "zh-guoyu", "zh-hakka",
+	"zh-min", "zh-min-nan", "zh-xiang",
+}
+
+// LanguageTag is a language tag as defined in RFC 5646, e.g. en-us or zh-hant-tw.
+// Subtags keep the case they were parsed with.
+type LanguageTag struct {
+	// Language is the primary language subtag, or "" for a private use or grandfathered tag
+	Language string
+	// ExtLang holds up to three extended language subtags
+	ExtLang []string
+	Script  string
+	Region  string
+	// Variants holds the variant subtags, e.g. "1996"
+	Variants []string
+	// Extensions holds each extension with its singleton, e.g. "u-co-phonebk"
+	Extensions []string
+	// PrivateUse holds the private use subtags with the leading "x", e.g. "x-foo"
+	PrivateUse string
+	// Grandfathered holds the whole tag if it is one of the grandfathered tags
+	Grandfathered string
+}
+
+// String returns the language tag with its subtags joined by hyphens
+func (t LanguageTag) String() string {
+	if t.Grandfathered != "" {
+		return t.Grandfathered
+	}
+	var s []string
+	for _, subtag := range append([]string{t.Language}, t.ExtLang...) {
+		if subtag != "" {
+			s = append(s, subtag)
+		}
+	}
+	if t.Script != "" {
+		s = append(s, t.Script)
+	}
+	if t.Region != "" {
+		s = append(s, t.Region)
+	}
+	s = append(s, t.Variants...)
+	s = append(s, t.Extensions...)
+	if t.PrivateUse != "" {
+		s = append(s, t.PrivateUse)
+	}
+	return strings.Join(s, "-")
+}
+
+// ParseLanguageTag parses an unquoted language tag as defined in RFC 5646.
+// Only the syntax is checked; subtags are not looked up in the IANA registry.
+// @param tag language tag, e.g. en-us
+// @return LanguageTag, or a ParseError with reason ReasonInvalidLanguage
+func ParseLanguageTag(tag string) (LanguageTag, error) {
+	for _, g := range grandfatheredTags {
+		if strings.EqualFold(tag, g) {
+			return LanguageTag{Grandfathered: tag}, nil
+		}
+	}
+
+	subtags := strings.Split(tag, "-")
+	// offsets[i] is the byte offset of subtags[i] in tag
+	offsets := make([]int, len(subtags))
+	for i := 1; i < len(subtags); i++ {
+		offsets[i] = offsets[i-1] + len(subtags[i-1]) + 1
+	}
+	for i, subtag := range subtags {
+		if subtag == "" || len(subtag) > 8 || !isAlphanumASCII(subtag) {
+			return LanguageTag{}, NewParseError(tag, ReasonInvalidLanguage, offsets[i], "invalid subtag %q in language tag: %s", subtag, tag)
+		}
+	}
+
+	var t LanguageTag
+	i := 0
+	next := func() string {
+		if i < len(subtags) {
+			return strings.ToLower(subtags[i])
+		}
+		return ""
+	}
+
+	if next() != "x" {
+		// language = 2*3ALPHA ["-" extlang] / 4ALPHA / 5*8ALPHA
+		if s := next(); len(s) < 2 || !isAlphaASCII(s) {
+			return LanguageTag{}, NewParseError(tag, ReasonInvalidLanguage, 0, "invalid primary language subtag in language tag: %s", tag)
+		}
+		t.Language = subtags[i]
+		i++
+		// extlang = 3ALPHA *2("-" 3ALPHA)
+		for len(t.Language) <= 3 && len(t.ExtLang) < 3 && len(next()) == 3 && isAlphaASCII(next()) {
+			t.ExtLang = append(t.ExtLang, subtags[i])
+			i++
+		}
+		// script = 4ALPHA
+		if s := next(); len(s) == 4 && isAlphaASCII(s) {
+			t.Script = subtags[i]
+			i++
+		}
+		// region = 2ALPHA / 3DIGIT
+		if s := next(); (len(s) == 2 && isAlphaASCII(s)) || (len(s) == 3 && isDigitASCII(s)) {
+			t.Region = subtags[i]
+			i++
+		}
+		// variant = 5*8alphanum / (DIGIT 3alphanum)
+		for s := next(); len(s) >= 5 || (len(s) == 4 && isDigit(rune(s[0]))); s = next() {
+			for _, v := range t.Variants {
+				if strings.EqualFold(v, s) {
+					return LanguageTag{}, NewParseError(tag, ReasonInvalidLanguage, offsets[i], "duplicate variant %q in language tag: %s", s, tag)
+				}
+			}
+			t.Variants = append(t.Variants, subtags[i])
+			i++
+		}
+		// extension = singleton 1*("-" (2*8alphanum))
+		seen := map[string]bool{}
+		for s := next(); len(s) == 1 && s != "x"; s = next() {
+			if seen[s] {
+				return LanguageTag{}, NewParseError(tag, ReasonInvalidLanguage, offsets[i], "duplicate extension %q in language tag: %s", s, tag)
+			}
+			seen[s] = true
+			start := i
+			i++
+			for len(next()) >= 2 {
+				i++
+			}
+			if i == start+1 {
+				return LanguageTag{}, NewParseError(tag, ReasonInvalidLanguage, offsets[start], "empty extension %q in language tag: %s", s, tag)
+			}
+			t.Extensions = append(t.Extensions, strings.Join(subtags[start:i], "-"))
+		}
+	}
+
+	// privateuse = "x" 1*("-" (1*8alphanum))
+	if next() == "x" {
+		if i == len(subtags)-1 {
+			return LanguageTag{}, NewParseError(tag, ReasonInvalidLanguage, offsets[i], "empty private use subtags in language tag: %s", tag)
+		}
+		t.PrivateUse = strings.Join(subtags[i:], "-")
+		i = len(subtags)
+	}
+
+	if i < len(subtags) {
+		return LanguageTag{}, NewParseError(tag, ReasonInvalidLanguage, offsets[i], "unexpected subtag %q in language tag: %s", subtags[i], tag)
+	}
+	return t, nil
+}
+
+// validateLanguage validates the value of the language attribute, in the quoted
+// form used by WellFormedName, as an RFC 5646 language tag.  Wildcards are
+// allowed at either end of the value, e.g. en\-* matches every English tag;
+// the literal characters of such a value must then be a part of some tag.
+// @param svalue quoted value that passed ValidateStringValue
+func validateLanguage(svalue string) error {
+	var literal []byte
+	// positions[i] is the byte offset in svalue of literal[i]
+	var positions []int
+	leading, trailing := false, false
+	quoted := false
+	for i := 0; i < len(svalue); i++ {
+		c := svalue[i]
+		switch {
+		case quoted:
+			quoted = false
+		case c == '\\':
+			quoted = true
+			continue
+		case c == '*' || c == '?':
+			if len(literal) == 0 {
+				leading = true
+			} else {
+				trailing = true
+			}
+			continue
+		}
+		literal = append(literal, c)
+		positions = append(positions, i)
+	}
+	positions = append(positions, len(svalue))
+
+	if !leading && !trailing {
+		if _, err := ParseLanguageTag(string(literal)); err != nil {
+			pe := err.(*ParseError)
+			pe.Input = svalue
+			pe.Offset = positions[pe.Offset]
+			return pe
+		}
+		return nil
+	}
+
+	// The subtags at either end may be cut by the wildcards, so only the
+	// characters and the lengths of the subtags can be checked.
+	subtags := strings.Split(string(literal), "-")
+	offset := 0
+	for i, subtag := range subtags {
+		partial := (i == 0 && leading) || (i == len(subtags)-1 && trailing)
+		if (subtag == "" && !partial) || len(subtag) > 8 || !isAlphanumASCII(subtag) {
+			return NewParseError(svalue, ReasonInvalidLanguage, positions[offset],
+				"invalid subtag %q in language pattern: %s", subtag, svalue)
+		}
+		offset += len(subtag) + 1
+	}
+	return nil
+}
+
+// LanguageTag returns the language attribute parsed as an RFC 5646 language tag.
+// @return the LanguageTag, or nil if language is not a literal string
+// (ANY, NA or a value with wildcards); an error if it is not a valid tag
+func (wfn WellFormedName) LanguageTag() (*LanguageTag, error) {
+	svalue, ok := wfn.Get(AttributeLanguage).(string)
+	if !ok || ContainsWildcards(svalue) {
+		return nil, nil
+	}
+	t, err := ParseLanguageTag(Unquote(svalue))
+	if err != nil {
+		return nil, SetAttribute(err, AttributeLanguage)
+	}
+	return &t, nil
+}
+
+// LanguageTag returns the language attribute parsed as an RFC 5646 language tag,
+// as WellFormedName.LanguageTag does
+func (t TypedWellFormedName) LanguageTag() (*LanguageTag, error) {
+	return t.WellFormedName().LanguageTag()
+}
+
+func isAlphaASCII(s string) bool {
+	for _, r := range s {
+		if !IsAlpha(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigitASCII(s string) bool {
+	for _, r := range s {
+		if !isDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphanumASCII(s string) bool {
+	for _, r := range s {
+		if !IsAlpha(r) && !isDigit(r) {
+			return false
+		}
+	}
+	return true
+}
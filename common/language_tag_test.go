@@ -0,0 +1,171 @@
+package common
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestParseLanguageTag(t *testing.T) {
+	vectors := []struct {
+		tag      string
+		expected LanguageTag
+		wantErr  error
+	}{{
+		tag:      "en",
+		expected: LanguageTag{Language: "en"},
+	}, {
+		tag:      "en-US",
+		expected: LanguageTag{Language: "en", Region: "US"},
+	}, {
+		tag:      "zh-Hant-TW",
+		expected: LanguageTag{Language: "zh", Script: "Hant", Region: "TW"},
+	}, {
+		tag:      "zh-yue-hk",
+		expected: LanguageTag{Language: "zh", ExtLang: []string{"yue"}, Region: "hk"},
+	}, {
+		tag:      "es-419",
+		expected: LanguageTag{Language: "es", Region: "419"},
+	}, {
+		tag:      "sl-rozaj-biske-1994",
+		expected: LanguageTag{Language: "sl", Variants: []string{"rozaj", "biske", "1994"}},
+	}, {
+		tag:      "de-DE-u-co-phonebk-x-private",
+		expected: LanguageTag{Language: "de", Region: "DE", Extensions: []string{"u-co-phonebk"}, PrivateUse: "x-private"},
+	}, {
+		tag:      "x-whatever",
+		expected: LanguageTag{PrivateUse: "x-whatever"},
+	}, {
+		tag:      "i-klingon",
+		expected: LanguageTag{Grandfathered: "i-klingon"},
+	}, {
+		tag:     "",
+		wantErr: ErrParse,
+	}, {
+		tag:     "sp2",
+		wantErr: ErrParse,
+	}, {
+		tag:     "en-",
+		wantErr: ErrParse,
+	}, {
+		tag:     "en_us",
+		wantErr: ErrParse,
+	}, {
+		tag:     "en-us-us",
+		wantErr: ErrParse,
+	}, {
+		tag:     "de-1901-1901",
+		wantErr: ErrParse,
+	}, {
+		tag:     "en-a-foo-a-bar",
+		wantErr: ErrParse,
+	}, {
+		tag:     "en-a",
+		wantErr: ErrParse,
+	}, {
+		tag:     "en-x",
+		wantErr: ErrParse,
+	}, {
+		tag:     "toolonglang",
+		wantErr: ErrParse,
+	},
+	}
+
+	for i, v := range vectors {
+		actual, err := ParseLanguageTag(v.tag)
+		if errors.Cause(err) != v.wantErr {
+			t.Errorf("test %d, Error: %v, want %v", i, errors.Cause(err), v.wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		if !reflect.DeepEqual(actual, v.expected) {
+			t.Errorf("test %d, Result: %#v, want %#v", i, actual, v.expected)
+		}
+		if actual.String() != v.tag {
+			t.Errorf("test %d, String: got %v, want %v", i, actual.String(), v.tag)
+		}
+	}
+}
+
+func TestValidateLanguage(t *testing.T) {
+	vectors := []struct {
+		svalue     string
+		wantErr    error
+		wantOffset int
+	}{{
+		svalue: `en\-us`,
+	}, {
+		svalue: `*\-us`,
+	}, {
+		svalue: `en\-*`,
+	}, {
+		svalue: `??`,
+	}, {
+		svalue:     `en\-us\-us`,
+		wantErr:    ErrParse,
+		wantOffset: 8,
+	}, {
+		svalue:     `en\-\-*`,
+		wantErr:    ErrParse,
+		wantOffset: 5,
+	}, {
+		svalue:     `*en\-`,
+		wantErr:    ErrParse,
+		wantOffset: 5,
+	}, {
+		svalue:     `en\-toolongsubtag*`,
+		wantErr:    ErrParse,
+		wantOffset: 4,
+	},
+	}
+
+	for i, v := range vectors {
+		err := validateLanguage(v.svalue)
+		if errors.Cause(err) != v.wantErr {
+			t.Errorf("test %d, Error: %v, want %v", i, errors.Cause(err), v.wantErr)
+		}
+		var pe *ParseError
+		if !errors.As(err, &pe) {
+			continue
+		}
+		if pe.Reason != ReasonInvalidLanguage {
+			t.Errorf("test %d, Reason: got %v, want %v", i, pe.Reason, ReasonInvalidLanguage)
+		}
+		if pe.Offset != v.wantOffset {
+			t.Errorf("test %d, Offset: got %v, want %v", i, pe.Offset, v.wantOffset)
+		}
+	}
+}
+
+func TestWellFormedNameLanguageTag(t *testing.T) {
+	vectors := []struct {
+		wfn      WellFormedName
+		expected *LanguageTag
+		wantErr  error
+	}{{
+		wfn:      WellFormedName{"language": `en\-us`},
+		expected: &LanguageTag{Language: "en", Region: "us"},
+	}, {
+		wfn: WellFormedName{"language": any},
+	}, {
+		wfn: WellFormedName{},
+	}, {
+		wfn: WellFormedName{"language": `en\-*`},
+	}, {
+		wfn:     WellFormedName{"language": "sp2"},
+		wantErr: ErrParse,
+	},
+	}
+
+	for i, v := range vectors {
+		actual, err := v.wfn.LanguageTag()
+		if errors.Cause(err) != v.wantErr {
+			t.Errorf("test %d, Error: %v, want %v", i, errors.Cause(err), v.wantErr)
+		}
+		if !reflect.DeepEqual(actual, v.expected) {
+			t.Errorf("test %d, Result: %v, want %v", i, actual, v.expected)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package common
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// ParseReason is enumeration for the reasons a CPE name cannot be parsed.
+type ParseReason int
+
+const (
+	// ReasonUnknown : no specific reason
+	ReasonUnknown ParseReason = iota
+	// ReasonBadPrefix : the name does not start with "cpe:/" or "cpe:2.3:"
+	ReasonBadPrefix
+	// ReasonComponentCount : the name has too many or too few components
+	ReasonComponentCount
+	// ReasonEmptyComponent : a formatted string has an empty component
+	ReasonEmptyComponent
+	// ReasonEmbeddedWildcard : an unquoted * or ? is embedded in a value
+	ReasonEmbeddedWildcard
+	// ReasonMultipleAsterisks : a value has more than one * in sequence
+	ReasonMultipleAsterisks
+	// ReasonSingleAsterisk : a value is a single *
+	ReasonSingleAsterisk
+	// ReasonUnquotedPunctuation : a value has unquoted punctuation
+	ReasonUnquotedPunctuation
+	// ReasonWhitespace : a value has whitespace
+	ReasonWhitespace
+	// ReasonNonPrintable : a value has a non printable character
+	ReasonNonPrintable
+	// ReasonQuotedHyphen : a value is a quoted hyphen
+	ReasonQuotedHyphen
+	// ReasonBadPercentEncoding : a URI has an unknown or truncated percent-encoded form
+	ReasonBadPercentEncoding
+	// ReasonInvalidPart : part is not one of 'a', 'o', 'h'
+	ReasonInvalidPart
+	// ReasonInvalidPacking : a packed URI edition does not have five values
+	ReasonInvalidPacking
+	// ReasonTrailingBackslash : a value ends with a backslash that quotes nothing
+	ReasonTrailingBackslash
+	// ReasonSyntax : the WFN text form is malformed
+	ReasonSyntax
+	// ReasonInvalidUTF8 : a value is not valid UTF-8
+	ReasonInvalidUTF8
+	// ReasonUnquotedNonASCII : a value has an unquoted non-ASCII character
+	ReasonUnquotedNonASCII
+	// ReasonInvalidLanguage : language is not a valid RFC 5646 language tag
+	ReasonInvalidLanguage
+)
+
+var parseReasons = map[ParseReason]string{
+	ReasonUnknown:             "UNKNOWN",
+	ReasonBadPrefix:           "BAD_PREFIX",
+	ReasonComponentCount:      "COMPONENT_COUNT",
+	ReasonEmptyComponent:      "EMPTY_COMPONENT",
+	ReasonEmbeddedWildcard:    "EMBEDDED_WILDCARD",
+	ReasonMultipleAsterisks:   "MULTIPLE_ASTERISKS",
+	ReasonSingleAsterisk:      "SINGLE_ASTERISK",
+	ReasonUnquotedPunctuation: "UNQUOTED_PUNCTUATION",
+	ReasonWhitespace:          "WHITESPACE",
+	ReasonNonPrintable:        "NON_PRINTABLE",
+	ReasonQuotedHyphen:        "QUOTED_HYPHEN",
+	ReasonBadPercentEncoding:  "BAD_PERCENT_ENCODING",
+	ReasonInvalidPart:         "INVALID_PART",
+	ReasonInvalidPacking:      "INVALID_PACKING",
+	ReasonTrailingBackslash:   "TRAILING_BACKSLASH",
+	ReasonSyntax:              "SYNTAX",
+	ReasonInvalidUTF8:         "INVALID_UTF8",
+	ReasonUnquotedNonASCII:    "UNQUOTED_NON_ASCII",
+	ReasonInvalidLanguage:     "INVALID_LANGUAGE",
+}
+
+// String returns string representation of the ParseReason
+func (r ParseReason) String() string {
+	if s, ok := parseReasons[r]; ok {
+		return s
+	}
+	return parseReasons[ReasonUnknown]
+}
+
+// ParseError describes why and where a CPE name or value failed to parse.
+// errors.Cause of a ParseError is ErrParse, and it can be retrieved
+// from wrapped errors with errors.As.
+type ParseError struct {
+	// Input is the name being parsed, or the value if it was validated on its own
+	Input string
+	// Component is the index of the offending component in the binding, or -1.
+	// For formatted strings 2 is part and 12 is other; for URIs 1 is part
+	// and 6 is the (possibly packed) edition; for the WFN text form it is
+	// the index of the attribute-value pair.
+	Component int
+	// Attribute is the name of the offending attribute, or ""
+	Attribute string
+	// Offset is the byte offset in Input of the offending character, or of the
+	// start of the offending component when the character is not known; -1 if unknown
+	Offset  int
+	Reason  ParseReason
+	Message string
+}
+
+// NewParseError returns a ParseError without component information.
+// @param input the string being checked
+// @param reason ParseReason
+// @param offset byte offset in input, or -1
+// @param format message format
+func NewParseError(input string, reason ParseReason, offset int, format string, args ...interface{}) *ParseError {
+	return &ParseError{
+		Input:     input,
+		Component: -1,
+		Offset:    offset,
+		Reason:    reason,
+		Message:   fmt.Sprintf(format, args...),
+	}
+}
+
+// Error returns the message with the position of the error, if known
+func (e *ParseError) Error() string {
+	s := e.Message
+	if e.Attribute != "" {
+		s += fmt.Sprintf(" (attribute %s)", e.Attribute)
+	}
+	if e.Input != "" && e.Offset >= 0 {
+		s += fmt.Sprintf(" at offset %d in %s", e.Offset, e.Input)
+	}
+	return s + ": " + ErrParse.Error()
+}
+
+// Cause returns ErrParse, so errors.Cause(err) == ErrParse holds for parse errors
+func (e *ParseError) Cause() error {
+	return ErrParse
+}
+
+// Unwrap returns ErrParse, so errors.Is(err, ErrParse) holds for parse errors
+func (e *ParseError) Unwrap() error {
+	return ErrParse
+}
+
+// SetAttribute records the attribute a ParseError in err belongs to, if not already set.
+// @param err error, possibly wrapping a ParseError
+// @param attribute attribute name
+// @return err
+func SetAttribute(err error, attribute string) error {
+	var pe *ParseError
+	if errors.As(err, &pe) && pe.Attribute == "" {
+		pe.Attribute = attribute
+	}
+	return err
+}
+
+// SetPosition records where a ParseError in err occurred, unless its component is already set.
+// The offset of the error is relative to the component; it is made relative
+// to the input by adding base. If exact is false, the offset within the
+// component is not meaningful and the offset is set to base.
+// @param err error, possibly wrapping a ParseError
+// @param input the name being parsed
+// @param component index of the component in the binding
+// @param base byte offset of the component in input
+// @param exact whether the offset of the error is relative to the component
+// @return err
+func SetPosition(err error, input string, component, base int, exact bool) error {
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Component >= 0 {
+		return err
+	}
+	pe.Input = input
+	pe.Component = component
+	if exact && pe.Offset >= 0 {
+		pe.Offset += base
+	} else {
+		pe.Offset = base
+	}
+	return err
+}
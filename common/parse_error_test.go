@@ -0,0 +1,116 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestParseError(t *testing.T) {
+	vectors := []struct {
+		err       error
+		component int
+		attribute string
+		offset    int
+		reason    ParseReason
+		input     string
+		message   string
+	}{{
+		err:       ValidateFS("cpe:2.3:a:microsoft:internet_explorer:8.0.6001:beta:*:sp2:*:*::*"),
+		component: 11,
+		offset:    62,
+		reason:    ReasonEmptyComponent,
+	}, {
+		err:       ValidateFS("cpe:2.4:a:microsoft:internet_explorer:8.0.6001:beta:*:sp2:*:*:*:*"),
+		component: 0,
+		offset:    0,
+		reason:    ReasonBadPrefix,
+	}, {
+		err:       ValidateURI("cpe:/a:microsoft:internet_explorer:8.0.6001:beta::sp2:invalid"),
+		component: 8,
+		offset:    53,
+		reason:    ReasonComponentCount,
+	}, {
+		err:       ValidateURI("CPE:/a:Microsoft:IE:8.0:beta::sp2:invalid"),
+		component: 8,
+		offset:    33,
+		reason:    ReasonComponentCount,
+		input:     "CPE:/a:Microsoft:IE:8.0:beta::sp2:invalid",
+		message:   "Error parsing URI.  Found 1 extra components at offset 33 in CPE:/a:Microsoft:IE:8.0:beta::sp2:invalid: Parse error",
+	}, {
+		err:       ValidateFS("CPE:2.4:a:Microsoft:IE:*:*:*:*:*:*:*:*"),
+		component: 0,
+		offset:    0,
+		reason:    ReasonBadPrefix,
+		input:     "CPE:2.4:a:Microsoft:IE:*:*:*:*:*:*:*:*",
+		message:   `Error: Formatted String must start with "cpe:2.3" at offset 0 in CPE:2.4:a:Microsoft:IE:*:*:*:*:*:*:*:*: Parse error`,
+	}, {
+		err:       ValidateFS("CPE:2.3:a:Microsoft:IE:*:*:*"),
+		component: 8,
+		offset:    28,
+		reason:    ReasonComponentCount,
+		input:     "CPE:2.3:a:Microsoft:IE:*:*:*",
+		message:   "Error parsing formatted string. Missing 5 components at offset 28 in CPE:2.3:a:Microsoft:IE:*:*:*: Parse error",
+	}, {
+		err:       errors.Wrap(ValidateStringValue("foo**"), "wrapped"),
+		component: -1,
+		offset:    3,
+		reason:    ReasonMultipleAsterisks,
+	}, {
+		err:       ValidateStringValue(`a\?b?c`),
+		component: -1,
+		offset:    4,
+		reason:    ReasonEmbeddedWildcard,
+	}, {
+		err:       ValidateStringValue(`?a\?\?b?c?`),
+		component: -1,
+		offset:    7,
+		reason:    ReasonEmbeddedWildcard,
+	}, {
+		err:       SetAttribute(errors.Wrap(ValidateStringValue(`\-`), "wrapped"), AttributeVendor),
+		component: -1,
+		attribute: AttributeVendor,
+		offset:    0,
+		reason:    ReasonQuotedHyphen,
+	}, {
+		err:       SetPosition(NewParseError("foo", ReasonWhitespace, 1, "whitespace"), "cpe:/a:foo", 2, 7, true),
+		component: 2,
+		offset:    8,
+		reason:    ReasonWhitespace,
+	}, {
+		err:       SetPosition(NewParseError("foo", ReasonWhitespace, 1, "whitespace"), "cpe:/a:foo", 2, 7, false),
+		component: 2,
+		offset:    7,
+		reason:    ReasonWhitespace,
+	},
+	}
+
+	for i, v := range vectors {
+		var pe *ParseError
+		if !errors.As(v.err, &pe) {
+			t.Errorf("test %d, errors.As: got false for %v", i, v.err)
+			continue
+		}
+		if errors.Cause(v.err) != ErrParse {
+			t.Errorf("test %d, Cause: got %v, want %v", i, errors.Cause(v.err), ErrParse)
+		}
+		if pe.Component != v.component {
+			t.Errorf("test %d, Component: got %v, want %v", i, pe.Component, v.component)
+		}
+		if pe.Attribute != v.attribute {
+			t.Errorf("test %d, Attribute: got %v, want %v", i, pe.Attribute, v.attribute)
+		}
+		if pe.Offset != v.offset {
+			t.Errorf("test %d, Offset: got %v, want %v", i, pe.Offset, v.offset)
+		}
+		if pe.Reason != v.reason {
+			t.Errorf("test %d, Reason: got %v, want %v", i, pe.Reason, v.reason)
+		}
+		if v.input != "" && pe.Input != v.input {
+			t.Errorf("test %d, Input: got %v, want %v", i, pe.Input, v.input)
+		}
+		if v.message != "" && v.err.Error() != v.message {
+			t.Errorf("test %d, Error: got %q, want %q", i, v.err.Error(), v.message)
+		}
+	}
+}
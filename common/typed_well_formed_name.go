@@ -0,0 +1,129 @@
+package common
+
+import (
+	"github.com/pkg/errors"
+)
+
+// TypedWellFormedName is a strongly typed representation of a Well Formed Name.
+// Unlike WellFormedName, each attribute is an AttributeValue, so callers do not
+// need to type-switch between strings and logical values.
+// The zero value has every attribute set to ANY.
+type TypedWellFormedName struct {
+	Part      AttributeValue
+	Vendor    AttributeValue
+	Product   AttributeValue
+	Version   AttributeValue
+	Update    AttributeValue
+	Edition   AttributeValue
+	Language  AttributeValue
+	SwEdition AttributeValue
+	TargetSw  AttributeValue
+	TargetHw  AttributeValue
+	Other     AttributeValue
+}
+
+// Attributes returns the names of the eleven WFN attributes in binding order
+func Attributes() []string {
+	result := make([]string, len(attributes))
+	copy(result, attributes)
+	return result
+}
+
+// NewTypedWellFormedName converts a WellFormedName to a TypedWellFormedName.
+// Missing attributes are converted to ANY.
+// @param wfn WellFormedName to convert
+// @return TypedWellFormedName, or an error if wfn holds an invalid value
+func NewTypedWellFormedName(wfn WellFormedName) (TypedWellFormedName, error) {
+	var t TypedWellFormedName
+	for attr, v := range wfn {
+		if !IsValidAttribute(attr) {
+			return TypedWellFormedName{}, errors.Wrapf(ErrIllegalAttribute, "unknown attribute: %s", attr)
+		}
+		if v == nil {
+			continue
+		}
+		if err := validateValue(attr, v); err != nil {
+			return TypedWellFormedName{}, errors.Wrapf(err, "invalid %s", attr)
+		}
+		av, err := NewAttributeValue(v)
+		if err != nil {
+			return TypedWellFormedName{}, err
+		}
+		*t.field(attr) = av
+	}
+	return t, nil
+}
+
+// WellFormedName converts the TypedWellFormedName back to a WellFormedName.
+// A part of ANY is omitted, as in NewWellFormedName, since part cannot
+// hold a logical value.
+func (t TypedWellFormedName) WellFormedName() WellFormedName {
+	wfn := WellFormedName{}
+	for _, attr := range attributes {
+		av := t.Get(attr)
+		if attr == AttributePart && av.IsANY() {
+			continue
+		}
+		wfn[attr] = av.Interface()
+	}
+	return wfn
+}
+
+// Get gets attribute
+// @param attribute String representing the component value to get
+// @return the AttributeValue of the given component, or ANY
+// if the attribute is not valid
+func (t TypedWellFormedName) Get(attribute string) AttributeValue {
+	if f := t.field(attribute); f != nil {
+		return *f
+	}
+	return NewAnyValue()
+}
+
+// Set sets the given attribute to value, validating it as WellFormedName.Set does
+// @param attribute String representing the component to set
+// @param value AttributeValue of the given component
+func (t *TypedWellFormedName) Set(attribute string, value AttributeValue) error {
+	f := t.field(attribute)
+	if f == nil {
+		return ErrIllegalAttribute
+	}
+	if err := validateValue(attribute, value.Interface()); err != nil {
+		return err
+	}
+	*f = value
+	return nil
+}
+
+// String returns the text form of the TypedWellFormedName, as WellFormedName.String does
+func (t TypedWellFormedName) String() string {
+	return t.WellFormedName().String()
+}
+
+func (t *TypedWellFormedName) field(attribute string) *AttributeValue {
+	switch attribute {
+	case AttributePart:
+		return &t.Part
+	case AttributeVendor:
+		return &t.Vendor
+	case AttributeProduct:
+		return &t.Product
+	case AttributeVersion:
+		return &t.Version
+	case AttributeUpdate:
+		return &t.Update
+	case AttributeEdition:
+		return &t.Edition
+	case AttributeLanguage:
+		return &t.Language
+	case AttributeSwEdition:
+		return &t.SwEdition
+	case AttributeTargetSw:
+		return &t.TargetSw
+	case AttributeTargetHw:
+		return &t.TargetHw
+	case AttributeOther:
+		return &t.Other
+	}
+	return nil
+}
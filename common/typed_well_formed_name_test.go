@@ -0,0 +1,133 @@
+package common
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestNewTypedWellFormedName(t *testing.T) {
+	vectors := []struct {
+		wfn      WellFormedName
+		expected TypedWellFormedName
+		wantErr  error
+	}{{
+		wfn: NewWellFormedName(),
+	}, {
+		wfn: WellFormedName{
+			"part":       "a",
+			"vendor":     "microsoft",
+			"product":    "internet_explorer????",
+			"version":    `8\.0\.6001`,
+			"update":     na,
+			"edition":    any,
+			"language":   any,
+			"sw_edition": any,
+			"target_sw":  any,
+			"target_hw":  any,
+			"other":      any,
+		},
+		expected: TypedWellFormedName{
+			Part:     NewStringValue("a"),
+			Vendor:   NewStringValue("microsoft"),
+			Product:  NewStringValue("internet_explorer????"),
+			Version:  NewStringValue(`8\.0\.6001`),
+			Update:   NewNAValue(),
+			Edition:  NewAnyValue(),
+			Language: NewAnyValue(),
+		},
+	}, {
+		wfn: WellFormedName{
+			"part": "x",
+		},
+		wantErr: ErrParse,
+	}, {
+		wfn: WellFormedName{
+			"part": any,
+		},
+		wantErr: ErrIllegalAttribute,
+	}, {
+		wfn: WellFormedName{
+			"foo": "bar",
+		},
+		wantErr: ErrIllegalAttribute,
+	}, {
+		wfn: WellFormedName{
+			"vendor": 1,
+		},
+		wantErr: ErrIllegalAttribute,
+	},
+	}
+
+	for i, v := range vectors {
+		actual, err := NewTypedWellFormedName(v.wfn)
+		if errors.Cause(err) != v.wantErr {
+			t.Errorf("test %d, Error: got %v, want %v", i, errors.Cause(err), v.wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		if !reflect.DeepEqual(actual, v.expected) {
+			t.Errorf("test %d, Result: %v, want %v", i, actual, v.expected)
+		}
+		// conversion back must be lossless
+		if back := actual.WellFormedName(); !reflect.DeepEqual(back, v.wfn) {
+			t.Errorf("test %d, WellFormedName: got %v, want %v", i, back, v.wfn)
+		}
+	}
+}
+
+func TestTypedWellFormedNameSet(t *testing.T) {
+	vectors := []struct {
+		attribute string
+		value     AttributeValue
+		wantErr   error
+	}{{
+		attribute: "part",
+		value:     NewStringValue("o"),
+	}, {
+		attribute: "part",
+		value:     NewAnyValue(),
+		wantErr:   ErrIllegalAttribute,
+	}, {
+		attribute: "vendor",
+		value:     NewNAValue(),
+	}, {
+		attribute: "vendor",
+		value:     NewStringValue("foo*bar"),
+		wantErr:   ErrParse,
+	}, {
+		attribute: "foo",
+		value:     NewStringValue("bar"),
+		wantErr:   ErrIllegalAttribute,
+	},
+	}
+
+	for i, v := range vectors {
+		var wfn TypedWellFormedName
+		err := wfn.Set(v.attribute, v.value)
+		if errors.Cause(err) != v.wantErr {
+			t.Errorf("test %d, Error: got %v, want %v", i, errors.Cause(err), v.wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		if actual := wfn.Get(v.attribute); actual != v.value {
+			t.Errorf("test %d, Result: %v, want %v", i, actual, v.value)
+		}
+	}
+}
+
+func TestTypedWellFormedNameString(t *testing.T) {
+	wfn := TypedWellFormedName{
+		Part:    NewStringValue("a"),
+		Vendor:  NewStringValue("microsoft"),
+		Version: NewNAValue(),
+	}
+	expected := `wfn:[part="a", vendor="microsoft", product=ANY, version=NA, update=ANY, edition=ANY, ` +
+		`language=ANY, sw_edition=ANY, target_sw=ANY, target_hw=ANY, other=ANY]`
+	if actual := wfn.String(); actual != expected {
+		t.Errorf("Result: %v, want %v", actual, expected)
+	}
+}
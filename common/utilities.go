@@ -2,9 +2,7 @@ package common
 
 import (
 	"strings"
-	"unicode"
-
-	"github.com/pkg/errors"
+	"unicode/utf8"
 )
 
 // IndexOf searches a string for the first occurrence of another string, starting
@@ -13,6 +11,12 @@ import (
 // @param str2 String to search for.
 // @param off Integer offset or -1 if not found.
 func IndexOf(str1, str2 string, off int) int {
+	if off < 0 {
+		off = 0
+	}
+	if off > len(str1) {
+		return -1
+	}
 	index := strings.Index(str1[off:], str2)
 	if index == -1 {
 		return -1
@@ -71,40 +75,104 @@ func CountEscapeCharacters(str string) (result int) {
 	return result
 }
 
-// LengthWithEscapeCharacters counts the number of characters with escape characters
+// LengthWithEscapeCharacters counts the number of characters, not bytes, in the
+// string, excluding escape characters
 func LengthWithEscapeCharacters(str string) (result int) {
-	return len(str) - CountEscapeCharacters(str)
+	return utf8.RuneCountInString(str) - CountEscapeCharacters(str)
+}
+
+// Quote converts an arbitrary string to a WFN attribute value string by quoting
+// every character other than ASCII letters, digits and underscores with a
+// backslash.  Non-ASCII characters are quoted one code point at a time, and the
+// result has no wildcards.
+// @param str string to quote
+// @return quoted string
+func Quote(str string) string {
+	var b strings.Builder
+	for _, r := range str {
+		if !IsAlpha(r) && !isDigit(r) && r != '_' {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
 }
 
-// GetUnescapedColonIndex searches a string for the first unescaped colon and returns the index of that colon
+// Unquote removes the escape characters from a WFN attribute value string.
+// A quoted backslash is kept as a single backslash.
+// @param str string to unquote
+// @return unquoted string
+func Unquote(str string) string {
+	if !strings.Contains(str, "\\") {
+		return str
+	}
+	var b strings.Builder
+	active := false
+	for _, s := range str {
+		if !active && s == '\\' {
+			active = true
+			continue
+		}
+		active = false
+		b.WriteRune(s)
+	}
+	return b.String()
+}
+
+// GetUnescapedColonIndex searches a string for the first unescaped colon and returns the index of that colon.
+// A backslash quotes the character following it, so the colon in "\\:" is unescaped.
 // @param str string to search
 // @return index of first unescaped colon, or 0 if not found
 func GetUnescapedColonIndex(str string) (idx int) {
-	for i, s := range str {
-		if s == ':' {
-			if i == 0 || str[i-1] != '\\' {
-				idx = i
-				break
-			}
+	for i := 0; i < len(str); i++ {
+		if str[i] == '\\' {
+			i++
+			continue
+		}
+		if str[i] == ':' {
+			return i
 		}
 	}
-	return idx
+	return 0
+}
+
+// IndexNth returns the index of the n'th occurrence of substr in s, or -1.
+// @param s string to search
+// @param substr string to search for
+// @param n 1-based occurrence
+func IndexNth(s, substr string, n int) int {
+	off := 0
+	for i := 0; i < n; i++ {
+		idx := strings.Index(s[off:], substr)
+		if idx == -1 {
+			return -1
+		}
+		if i == n-1 {
+			return off + idx
+		}
+		off += idx + len(substr)
+	}
+	return -1
 }
 
 // IsAlphanum returns true if the string contains only
-// alphanumeric characters or the underscore character,
+// ASCII alphanumeric characters or the underscore character,
 // false otherwise.
 // @param c the string in question
 // @return true if c is alphanumeric or underscore, false if not
 func IsAlphanum(s string) bool {
 	for _, r := range s {
-		if !IsAlpha(r) && !unicode.IsDigit(r) && r != '_' {
+		if !IsAlpha(r) && !isDigit(r) && r != '_' {
 			return false
 		}
 	}
 	return true
 }
 
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
 // IsAlpha returns true if the rune contains only 'a'..'z' and 'A'..'Z'.
 func IsAlpha(r rune) bool {
 	if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
@@ -120,14 +188,17 @@ func IsAlpha(r rune) bool {
 //   A URI may not contain more than 7 components
 // If either rule is violated, a ParseErr is thrown.
 func ValidateURI(in string) error {
-	in = strings.ToLower(in)
 	// make sure uri starts with cpe:/
-	if !strings.HasPrefix(in, "cpe:/") {
-		return errors.Wrapf(ErrParse, "Error: URI must start with 'cpe:/'.  Given: %s", in)
+	if !strings.HasPrefix(strings.ToLower(in), "cpe:/") {
+		err := NewParseError(in, ReasonBadPrefix, 0, "Error: URI must start with 'cpe:/'")
+		err.Component = 0
+		return err
 	}
 	// make sure uri doesn't contain more than 7 colons
 	if count := strings.Count(in, ":"); count > 7 {
-		return errors.Wrapf(ErrParse, "Error parsing URI.  Found %d extra components in: %s", count-7, in)
+		err := NewParseError(in, ReasonComponentCount, IndexNth(in, ":", 8), "Error parsing URI.  Found %d extra components", count-7)
+		err.Component = 8
+		return err
 	}
 	return nil
 }
@@ -140,31 +211,43 @@ func ValidateURI(in string) error {
 //    A formatted string must not contain empty components
 // If any rule is violated, a ParseException is thrown.
 func ValidateFS(in string) error {
-	in = strings.ToLower(in)
-	if !strings.HasPrefix(in, "cpe:2.3:") {
-		return errors.Wrapf(ErrParse, "Error: Formatted String must start with \"cpe:2.3\". Given: %s", in)
+	if !strings.HasPrefix(strings.ToLower(in), "cpe:2.3:") {
+		err := NewParseError(in, ReasonBadPrefix, 0, "Error: Formatted String must start with \"cpe:2.3\"")
+		err.Component = 0
+		return err
 	}
 	// make sure fs contains exactly 12 unquoted colons
 	count := 0
-	for i := 0; i != len(in); i++ {
+	extraIdx := -1
+	for i := 0; i < len(in); i++ {
+		if in[i] == '\\' {
+			// skip the quoted character
+			i++
+			continue
+		}
 		if in[i] == ':' {
-			if i == 0 || in[i-1] != '\\' {
-				count++
-			} else {
-				continue
+			count++
+			if count == 13 {
+				extraIdx = i
 			}
 			if i < len(in)-1 && in[i+1] == ':' {
-				return errors.Wrap(ErrParse, "Error parsing formatted string. Found empty component")
+				err := NewParseError(in, ReasonEmptyComponent, i+1, "Error parsing formatted string. Found empty component")
+				err.Component = count
+				return err
 			}
 		}
 	}
 	if count > 12 {
 		extra := count - 12
-		return errors.Wrapf(ErrParse, "Error parsing formatted string. Found %d components in: %s", extra, in)
+		err := NewParseError(in, ReasonComponentCount, extraIdx, "Error parsing formatted string. Found %d extra components", extra)
+		err.Component = 13
+		return err
 	}
 	if count < 12 {
 		missing := 12 - count
-		return errors.Wrapf(ErrParse, "Error parsing formatted string. Missing %d components in: %s", missing, in)
+		err := NewParseError(in, ReasonComponentCount, len(in), "Error parsing formatted string. Missing %d components", missing)
+		err.Component = count + 1
+		return err
 	}
 	return nil
 }
@@ -67,6 +67,9 @@ func TestLengthWithEscapeCharacters(t *testing.T) {
 	}, {
 		s:        `\\abc\\\d`,
 		expected: 6,
+	}, {
+		s:        `m\üller`,
+		expected: 6,
 	},
 	}
 
@@ -180,6 +183,63 @@ func TestContainsQuestions(t *testing.T) {
 	}
 }
 
+func TestQuote(t *testing.T) {
+	vectors := []struct {
+		s        string
+		expected string
+	}{{
+		s:        "abc_123",
+		expected: "abc_123",
+	}, {
+		s:        "8.0.6001",
+		expected: `8\.0\.6001`,
+	}, {
+		s:        `foo\bar`,
+		expected: `foo\\bar`,
+	}, {
+		s:        "m\u00fcller",
+		expected: `m\üller`,
+	},
+	}
+
+	for i, v := range vectors {
+		actual := Quote(v.s)
+		if actual != v.expected {
+			t.Errorf("test %d, Result: got %v, want %v", i, actual, v.expected)
+		}
+		if Unquote(actual) != v.s {
+			t.Errorf("test %d, Unquote: got %v, want %v", i, Unquote(actual), v.s)
+		}
+	}
+}
+
+func TestUnquote(t *testing.T) {
+	vectors := []struct {
+		s        string
+		expected string
+	}{{
+		s:        `abc`,
+		expected: "abc",
+	}, {
+		s:        `8\.0\.6001`,
+		expected: "8.0.6001",
+	}, {
+		s:        `foo\\bar`,
+		expected: `foo\bar`,
+	}, {
+		s:        `sp\?`,
+		expected: "sp?",
+	},
+	}
+
+	for i, v := range vectors {
+		actual := Unquote(v.s)
+		if actual != v.expected {
+			t.Errorf("test %d, Result: got %v, want %v", i, actual, v.expected)
+		}
+	}
+}
+
 func TestGetUnescapedColonIndex(t *testing.T) {
 	vectors := []struct {
 		s        string
@@ -199,6 +259,9 @@ func TestGetUnescapedColonIndex(t *testing.T) {
 	}, {
 		s:        `abc\:def:ghi`,
 		expected: 8,
+	}, {
+		s:        `abc\\:def`,
+		expected: 5,
 	},
 	}
 
@@ -232,6 +295,9 @@ func TestIsAlphanum(t *testing.T) {
 	}, {
 		s:        "abcあABC",
 		expected: false,
+	}, {
+		s:        "\u0661\u0662",
+		expected: false,
 	},
 	}
 
@@ -318,6 +384,12 @@ func TestValidateFS(t *testing.T) {
 	}, {
 		s:       "cpe:2.3:a:microsoft:internet_explorer:8.0.6001:beta:*:sp2:",
 		wantErr: ErrParse,
+	}, {
+		// the colon after a quoted backslash is a delimiter
+		s: `cpe:2.3:a:foo\\:bar:*:*:*:*:*:*:*:*`,
+	}, {
+		s:       `cpe:2.3:a:foo\\:*:*:*:*:*:*:*:*:*:*`,
+		wantErr: ErrParse,
 	},
 	}
 
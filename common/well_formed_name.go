@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/pkg/errors"
 )
@@ -30,6 +31,9 @@ var (
 	ErrIllegalAttribute = errors.New("Illegal attribute")
 	// ErrParse is returned when parse error
 	ErrParse = errors.New("Parse error")
+
+	// anyValue is ANY converted to an interface once, so that Get does not allocate
+	anyValue interface{} = LogicalValue{Any: true}
 )
 
 // WellFormedName represents a Well Formed Name, as defined
@@ -84,8 +88,7 @@ func (wfn WellFormedName) Get(attribute string) interface{} {
 	if v, ok := wfn[attribute]; ok {
 		return v
 	}
-	any, _ := NewLogicalValue("ANY")
-	return any
+	return anyValue
 }
 
 // Set sets the given attribute to value, if the attribute is in the list of permissible components
@@ -101,11 +104,24 @@ func (wfn WellFormedName) Set(attribute string, value interface{}) (err error) {
 		return nil
 	}
 
+	if err = validateValue(attribute, value); err != nil {
+		return err
+	}
+
+	// should be good to go
+	wfn[attribute] = value
+
+	return nil
+}
+
+// validateValue validates a value stored in a WellFormedName for the given attribute
+// @param attribute String representing the component the value belongs to
+// @param value Object representing the value of the given component
+func validateValue(attribute string, value interface{}) (err error) {
 	if _, ok := value.(LogicalValue); ok {
 		if attribute == AttributePart {
 			return errors.Wrap(ErrIllegalAttribute, "part component cannot be a logical value")
 		}
-		wfn[attribute] = value
 		return nil
 	}
 
@@ -115,19 +131,23 @@ func (wfn WellFormedName) Set(attribute string, value interface{}) (err error) {
 	}
 
 	if err = ValidateStringValue(svalue); err != nil {
-		return errors.Wrap(err, "Failed to validate a value")
+		return errors.Wrap(SetAttribute(err, attribute), "Failed to validate a value")
 	}
 
 	// part must be a, o, or h
 	if attribute == AttributePart {
 		if svalue != "a" && svalue != "o" && svalue != "h" {
-			return errors.Wrapf(ErrParse, "part component must be one of the following: 'a', 'o', 'h': %s", svalue)
+			err = NewParseError(svalue, ReasonInvalidPart, 0, "part component must be one of the following: 'a', 'o', 'h': %s", svalue)
+			return SetAttribute(err, attribute)
 		}
 	}
 
-	// should be good to go
-	wfn[attribute] = value
-
+	// language must be a language tag as defined in RFC 5646
+	if attribute == AttributeLanguage {
+		if err = validateLanguage(svalue); err != nil {
+			return SetAttribute(err, attribute)
+		}
+	}
 	return nil
 }
 
@@ -139,22 +159,50 @@ func (wfn WellFormedName) GetString(attribute string) string {
 	return fmt.Sprintf("%s", wfn.Get(attribute))
 }
 
-// String returns string representation of the WellFormedName
+// String returns the text form of the WellFormedName, as defined in
+// NISTIR 7695 section 5.4, e.g. wfn:[part="a", vendor="microsoft", product=ANY, ...].
+// Logical values are bare and string values are enclosed in double quotes.
+// Every attribute is listed, in the order of the specification.
 func (wfn WellFormedName) String() string {
-	s := "wfn:["
+	var s []string
 	for _, attr := range attributes {
-		s += fmt.Sprintf("%s=", attr)
-		o := wfn.Get(attr)
-		if lv, ok := o.(LogicalValue); ok {
-			s += fmt.Sprintf("%s, ", lv)
-		} else {
-			s += fmt.Sprintf("\"%s\", ", o)
+		s = append(s, attr+"="+textValue(wfn.Get(attr)))
+	}
+	return "wfn:[" + strings.Join(s, ", ") + "]"
+}
+
+// textValue binds a value to the text form of a WFN.  A double quote or a
+// trailing backslash that is not quoted in a string value is quoted, so that
+// the text form of an unvalidated value can still be read back.
+func textValue(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return LogicalValue{Any: true}.String()
+	case LogicalValue:
+		return t.String()
+	case AttributeValue:
+		return textValue(t.Interface())
+	case string:
+		return `"` + quoteText(t) + `"`
+	}
+	return `"` + quoteText(fmt.Sprint(v)) + `"`
+}
+
+func quoteText(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s):
+			b.WriteString(s[i : i+2])
+			i++
+		case s[i] == '\\' || s[i] == '"':
+			b.WriteByte('\\')
+			b.WriteByte(s[i])
+		default:
+			b.WriteByte(s[i])
 		}
 	}
-	s = strings.TrimSpace(s)
-	s = strings.TrimRight(s, ",")
-	s += "]"
-	return s
+	return b.String()
 }
 
 // IsValidAttribute validates an attribute name
@@ -167,32 +215,47 @@ func IsValidAttribute(attribute string) (valid bool) {
 	return valid
 }
 
-// ValidateStringValue validates an string value
+// ValidateStringValue validates an string value.
+// A value must be valid UTF-8.  Punctuation other than underscores and the
+// wildcards * and ?, and every non-ASCII character, must be quoted with a
+// backslash; see Quote.  Symbols such as + and $ are accepted unquoted.
 func ValidateStringValue(svalue string) (err error) {
 	// svalue has more than one unquoted star
-	if strings.HasPrefix(svalue, "**") || strings.HasSuffix(svalue, "**") {
-		return errors.Wrapf(ErrParse, "component cannot contain more than one * in sequence: %s", svalue)
+	if strings.HasPrefix(svalue, "**") {
+		return NewParseError(svalue, ReasonMultipleAsterisks, 0, "component cannot contain more than one * in sequence: %s", svalue)
+	}
+	if strings.HasSuffix(svalue, "**") {
+		return NewParseError(svalue, ReasonMultipleAsterisks, len(svalue)-2, "component cannot contain more than one * in sequence: %s", svalue)
 	}
 
 	prev := ' ' // dummy value
 	for i, r := range svalue {
+		if r == utf8.RuneError {
+			if _, size := utf8.DecodeRuneInString(svalue[i:]); size == 1 {
+				return NewParseError(svalue, ReasonInvalidUTF8, i, "encountered invalid UTF-8 in: %q", svalue)
+			}
+		}
 		// check for printable characters - no control characters
 		if !unicode.IsPrint(r) {
-			return errors.Wrapf(ErrParse, "encountered non printable character in: %s", svalue)
+			return NewParseError(svalue, ReasonNonPrintable, i, "encountered non printable character in: %s", svalue)
 		}
 		// svalue has whitespace
 		if unicode.IsSpace(r) {
-			return errors.Wrapf(ErrParse, "component cannot contain whitespace:: %s", svalue)
+			return NewParseError(svalue, ReasonWhitespace, i, "component cannot contain whitespace:: %s", svalue)
+		}
+		// non-ASCII characters are quoted like punctuation
+		if r > unicode.MaxASCII && prev != '\\' {
+			return NewParseError(svalue, ReasonUnquotedNonASCII, i, "component cannot contain unquoted non-ASCII characters: %s", svalue)
 		}
 		if unicode.IsPunct(r) && prev != '\\' && r != '\\' {
 			// svalue has an unquoted *
 			if r == '*' && (i != 0 && i != len(svalue)-1) {
-				return errors.Wrapf(ErrParse, "component cannot contain embedded *: %s", svalue)
+				return NewParseError(svalue, ReasonEmbeddedWildcard, i, "component cannot contain embedded *: %s", svalue)
 			}
 
 			if r != '*' && r != '?' && r != '_' {
 				// svalue has unquoted punctuation embedded
-				return errors.Wrapf(ErrParse, "component cannot contain unquoted punctuation: %s", svalue)
+				return NewParseError(svalue, ReasonUnquotedPunctuation, i, "component cannot contain unquoted punctuation: %s", svalue)
 			}
 		}
 		prev = r
@@ -206,18 +269,32 @@ func ValidateStringValue(svalue string) (err error) {
 
 		s := strings.Trim(svalue, "?")
 		if ContainsQuestions(s) {
-			return errors.Wrapf(ErrParse, "component cannot contain embedded ?: %s", svalue)
+			offset := len(svalue) - len(strings.TrimLeft(svalue, "?"))
+			for i := 0; i < len(s) && s[i] != '?'; i++ {
+				if s[i] == '\\' {
+					// skip the quoted character
+					offset++
+					i++
+				}
+				offset++
+			}
+			return NewParseError(svalue, ReasonEmbeddedWildcard, offset, "component cannot contain embedded ?: %s", svalue)
 		}
 	}
 
+	// a backslash must quote the following character
+	if backslashes := len(svalue) - len(strings.TrimRight(svalue, `\`)); backslashes%2 == 1 {
+		return NewParseError(svalue, ReasonTrailingBackslash, len(svalue)-1, "component cannot end with an unquoted backslash: %s", svalue)
+	}
+
 	// single asterisk is not allowed
 	if svalue == "*" {
-		return errors.Wrapf(ErrParse, "component cannot be a single *: %s", svalue)
+		return NewParseError(svalue, ReasonSingleAsterisk, 0, "component cannot be a single *: %s", svalue)
 	}
 
 	// quoted hyphen not allowed by itself
 	if svalue == `\-` {
-		return errors.Wrapf(ErrParse, "component cannot be quoted hyphen: %s", svalue)
+		return NewParseError(svalue, ReasonQuotedHyphen, 0, "component cannot be quoted hyphen: %s", svalue)
 	}
 
 	return nil
@@ -227,6 +227,37 @@ func TestWellFormedNameSet(t *testing.T) {
 		attribute: "version",
 		value:     "**1.2.3",
 		wantErr:   ErrParse,
+	}, {
+		wfn:       WellFormedName{},
+		attribute: "language",
+		value:     `en\-us`,
+		expected: WellFormedName{
+			"language": `en\-us`,
+		},
+	}, {
+		wfn:       WellFormedName{},
+		attribute: "language",
+		value:     `en\-*`,
+		expected: WellFormedName{
+			"language": `en\-*`,
+		},
+	}, {
+		wfn:       WellFormedName{},
+		attribute: "language",
+		value:     "sp2", // language must be a language tag
+		wantErr:   ErrParse,
+	}, {
+		wfn:       WellFormedName{},
+		attribute: "language",
+		value:     `en\-\-*`,
+		wantErr:   ErrParse,
+	}, {
+		wfn:       WellFormedName{},
+		attribute: "vendor",
+		value:     "c++",
+		expected: WellFormedName{
+			"vendor": "c++",
+		},
 	},
 	}
 
@@ -266,6 +297,24 @@ func TestWellFormedNameString(t *testing.T) {
 			"other":      any,
 		},
 		expected: `wfn:[part="a", vendor="microsoft", product="windows_7", version=NA, update=ANY, edition=ANY, language=ANY, sw_edition=ANY, target_sw=ANY, target_hw=ANY, other=ANY]`,
+	}, {
+		wfn: WellFormedName{
+			"part":    "a",
+			"vendor":  `foo\\bar\"`,
+			"product": `8\.*`,
+			"version": nil,
+			"update":  NewStringValue("sp?"),
+		},
+		expected: `wfn:[part="a", vendor="foo\\bar\"", product="8\.*", version=ANY, update="sp?", edition=ANY, language=ANY, sw_edition=ANY, target_sw=ANY, target_hw=ANY, other=ANY]`,
+	}, {
+		// unquoted double quotes and trailing backslashes are quoted
+		wfn: WellFormedName{
+			"part":    "a",
+			"vendor":  `say "hi"`,
+			"product": `foo\`,
+			"version": 1,
+		},
+		expected: `wfn:[part="a", vendor="say \"hi\"", product="foo\\", version="1", update=ANY, edition=ANY, language=ANY, sw_edition=ANY, target_sw=ANY, target_hw=ANY, other=ANY]`,
 	},
 	}
 
@@ -321,6 +370,26 @@ func TestValidateStringValue(t *testing.T) {
 	}, {
 		svalue:  `\-`,
 		wantErr: ErrParse,
+	}, {
+		// empty values and unquoted symbols are accepted as they always were
+		svalue: "",
+	}, {
+		svalue: "foo$bar",
+	}, {
+		svalue: "c++",
+	}, {
+		svalue:  `foo\`,
+		wantErr: ErrParse,
+	}, {
+		svalue: `foo\\`,
+	}, {
+		svalue:  "m\u00fcller",
+		wantErr: ErrParse,
+	}, {
+		svalue: `m\üller`,
+	}, {
+		svalue:  "foo\xffbar",
+		wantErr: ErrParse,
 	},
 	}
 
@@ -0,0 +1,135 @@
+package dictionary
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/xml"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// Decoder reads dictionary items one at a time, so a dictionary of any size
+// can be processed with bounded memory. Gzip-compressed input is detected
+// and decompressed transparently. An item with an invalid name or date is
+// skipped and counted by Skipped; malformed XML ends the input.
+//
+//	d, err := dictionary.NewDecoder(r)
+//	...
+//	defer d.Close()
+//	for d.Next() {
+//		item := d.Item()
+//		...
+//	}
+//	if err := d.Err(); err != nil {
+//		...
+//	}
+type Decoder struct {
+	decoder   *xml.Decoder
+	gzip      *gzip.Reader
+	generator Generator
+	item      Item
+	skipped   int
+	skipErr   error
+	err       error
+}
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// NewDecoder returns a Decoder reading from r.
+// @param r XML dictionary, optionally gzip-compressed
+// @return Decoder, or an error if the gzip header is invalid
+func NewDecoder(r io.Reader) (*Decoder, error) {
+	br := bufio.NewReader(r)
+	d := &Decoder{}
+	if magic, err := br.Peek(len(gzipMagic)); err == nil && string(magic) == string(gzipMagic) {
+		if d.gzip, err = gzip.NewReader(br); err != nil {
+			return nil, errors.Wrap(err, "Failed to decompress dictionary")
+		}
+		d.decoder = xml.NewDecoder(d.gzip)
+	} else {
+		d.decoder = xml.NewDecoder(br)
+	}
+	return d, nil
+}
+
+// Next advances the Decoder to the next item, which is then available through Item.
+// It returns false at the end of the input or on error; Err distinguishes the two.
+func (d *Decoder) Next() bool {
+	if d.err != nil {
+		return false
+	}
+	for {
+		token, err := d.decoder.Token()
+		if err == io.EOF {
+			return false
+		}
+		if err != nil {
+			d.err = errors.Wrap(err, "Failed to decode dictionary")
+			return false
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "generator":
+			var x xmlGenerator
+			if err = d.decoder.DecodeElement(&x, &start); err != nil {
+				d.err = errors.Wrap(err, "Failed to decode generator")
+				return false
+			}
+			if d.generator, d.err = x.convert(); d.err != nil {
+				return false
+			}
+		case "cpe-item":
+			var x xmlCpeItem
+			if err = d.decoder.DecodeElement(&x, &start); err != nil {
+				d.err = errors.Wrap(err, "Failed to decode cpe-item")
+				return false
+			}
+			item, err := x.convert()
+			if err != nil {
+				d.skipped++
+				d.skipErr = err
+				continue
+			}
+			d.item = item
+			return true
+		}
+	}
+}
+
+// Item returns the item read by the last call to Next.
+func (d *Decoder) Item() Item {
+	return d.item
+}
+
+// Generator returns the generator metadata. The generator precedes the items,
+// so it is available once Next has returned the first item.
+func (d *Decoder) Generator() Generator {
+	return d.generator
+}
+
+// Skipped returns the number of items skipped so far.
+func (d *Decoder) Skipped() int {
+	return d.skipped
+}
+
+// SkipErr returns the error of the last item skipped, or nil if none was.
+func (d *Decoder) SkipErr() error {
+	return d.skipErr
+}
+
+// Err returns the error that ended Next, if any.
+func (d *Decoder) Err() error {
+	return d.err
+}
+
+// Close releases the gzip reader, if any. It does not close the underlying reader.
+func (d *Decoder) Close() error {
+	if d.gzip != nil {
+		return d.gzip.Close()
+	}
+	return nil
+}
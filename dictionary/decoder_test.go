@@ -0,0 +1,119 @@
+package dictionary
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecoder(t *testing.T) {
+	plain, err := ioutil.ReadFile("testdata/dictionary.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var compressed bytes.Buffer
+	w := gzip.NewWriter(&compressed)
+	w.Write(plain)
+	w.Close()
+
+	vectors := []struct {
+		name  string
+		input []byte
+	}{{
+		name:  "plain",
+		input: plain,
+	}, {
+		name:  "gzip",
+		input: compressed.Bytes(),
+	},
+	}
+
+	for _, v := range vectors {
+		d, err := NewDecoder(bytes.NewReader(v.input))
+		if err != nil {
+			t.Fatalf("%s, Unexpected error: %s", v.name, err)
+		}
+		var names []string
+		for d.Next() {
+			names = append(names, d.Item().Name)
+		}
+		if err = d.Err(); err != nil {
+			t.Errorf("%s, Unexpected error: %s", v.name, err)
+		}
+		if len(names) != 9 || names[1] != "cpe:/a:apache:tomcat:8.0.0:rc1" {
+			t.Errorf("%s, Items: got %v", v.name, names)
+		}
+		if d.Generator().SchemaVersion != "2.3" {
+			t.Errorf("%s, Generator: got %v", v.name, d.Generator())
+		}
+		if err = d.Close(); err != nil {
+			t.Errorf("%s, Unexpected error: %s", v.name, err)
+		}
+	}
+}
+
+func TestDecoderSkip(t *testing.T) {
+	input := `<cpe-list>
+  <cpe-item name="cpe:/a:foo:bar"><cpe23-item name="cpe:2.3:a:foo:bar:*:*:*:*:*:*:*:*"/></cpe-item>
+  <cpe-item name="cpe:/a:foo"><cpe23-item name="cpe:2.3:a:foo"/></cpe-item>
+  <cpe-item name="cpe:/a:foo:qux" deprecation_date="yesterday"><cpe23-item name="cpe:2.3:a:foo:qux:*:*:*:*:*:*:*:*"/></cpe-item>
+  <cpe-item name="cpe:/a:foo:baz"><cpe23-item name="cpe:2.3:a:foo:baz:*:*:*:*:*:*:*:*"/></cpe-item>
+</cpe-list>`
+	d, err := NewDecoder(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	var names []string
+	for d.Next() {
+		names = append(names, d.Item().Name)
+	}
+	if err = d.Err(); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+	if expected := []string{"cpe:/a:foo:bar", "cpe:/a:foo:baz"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("Items: got %v, want %v", names, expected)
+	}
+	if d.Skipped() != 2 {
+		t.Errorf("Skipped: got %d, want 2", d.Skipped())
+	}
+	if err = d.SkipErr(); err == nil || !strings.Contains(err.Error(), "deprecation date") {
+		t.Errorf("SkipErr: got %v, want the error of the last skipped item", err)
+	}
+
+	if _, err = Parse(strings.NewReader(input)); err == nil {
+		t.Errorf("Parse: expected error")
+	}
+}
+
+func TestDecoderError(t *testing.T) {
+	input := `<cpe-list>
+  <cpe-item name="cpe:/a:foo:bar"><cpe23-item name="cpe:2.3:a:foo:bar:*:*:*:*:*:*:*:*"/></cpe-item>
+  <cpe-item name="cpe:/a:foo"><cpe23-item name="cpe:2.3:a:foo"></cpe-item>
+  <cpe-item name="cpe:/a:foo:baz"><cpe23-item name="cpe:2.3:a:foo:baz:*:*:*:*:*:*:*:*"/></cpe-item>
+</cpe-list>`
+	d, err := NewDecoder(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !d.Next() || d.Item().Name != "cpe:/a:foo:bar" {
+		t.Fatalf("first item: got %v", d.Item())
+	}
+	if d.Next() {
+		t.Errorf("second item: expected failure, got %v", d.Item())
+	}
+	if d.Err() == nil {
+		t.Errorf("expected error")
+	}
+	if d.Next() {
+		t.Errorf("Next after error: expected false")
+	}
+}
+
+func TestNewDecoderError(t *testing.T) {
+	if _, err := NewDecoder(bytes.NewReader([]byte{0x1f, 0x8b, 0x00})); err == nil {
+		t.Errorf("expected error")
+	}
+}
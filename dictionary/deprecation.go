@@ -0,0 +1,157 @@
+package dictionary
+
+import (
+	"time"
+
+	"github.com/knqyf263/go-cpe/common"
+	"github.com/knqyf263/go-cpe/matching"
+	"github.com/knqyf263/go-cpe/naming"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrNotFound is returned when a name is not in the dictionary
+	ErrNotFound = errors.New("Name not found in dictionary")
+	// ErrDeprecationCycle is returned when deprecation chains lead back to a deprecated name
+	ErrDeprecationCycle = errors.New("Deprecation cycle")
+)
+
+// Step is a single deprecation followed while resolving a name.
+type Step struct {
+	// From is the formatted string of the deprecated name
+	From string
+	// To is the formatted string of the replacement
+	To   string
+	Type DeprecationType
+	Date time.Time
+}
+
+// Resolution is the result of following the deprecation chains of a name.
+type Resolution struct {
+	// Current are the official, not deprecated, names the chains lead to
+	Current []Item
+	// Chain are the deprecations followed, in depth-first order
+	Chain []Step
+	// Removed are deprecated names without any replacement
+	Removed []Item
+	// Missing are replacement names that are not in the dictionary
+	Missing []string
+}
+
+// Resolve follows the deprecation chains of a name to the current official names.
+// A name that is not deprecated resolves to itself.
+// @param wfn WFN equal to a dictionary item
+// @return Resolution, ErrNotFound if the name is not in the dictionary,
+// or ErrDeprecationCycle if a chain leads back to a name on it
+func (dict *Dictionary) Resolve(wfn common.WellFormedName) (*Resolution, error) {
+	start := dict.find(wfn)
+	if len(start) == 0 {
+		return nil, errors.Wrapf(ErrNotFound, "name: %s", naming.BindToFS(wfn))
+	}
+	r := &resolver{
+		dict:   dict,
+		result: &Resolution{},
+		onPath: map[int]bool{},
+		done:   map[int]bool{},
+	}
+	for _, i := range start {
+		if err := r.resolve(i); err != nil {
+			return nil, err
+		}
+	}
+	return r.result, nil
+}
+
+// ResolveFS follows the deprecation chains of a formatted string, as Resolve does.
+func (dict *Dictionary) ResolveFS(fs string) (*Resolution, error) {
+	wfn, err := naming.UnbindFS(fs)
+	if err != nil {
+		return nil, err
+	}
+	return dict.Resolve(wfn)
+}
+
+// find returns the positions of the items equal to the name.
+func (dict *Dictionary) find(wfn common.WellFormedName) []int {
+	var result []int
+	for _, i := range dict.match(wfn) {
+		if matching.IsEqual(wfn, dict.Items[i].WFN) {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+type replacement struct {
+	name string
+	wfn  common.WellFormedName
+	typ  DeprecationType
+	date time.Time
+}
+
+// replacements returns the names replacing an item, preferring the CPE 2.3
+// deprecations over the CPE 2.2 deprecated_by attribute.
+func (item Item) replacements() ([]replacement, error) {
+	var result []replacement
+	for _, d := range item.Deprecations {
+		for _, by := range d.DeprecatedBy {
+			result = append(result, replacement{name: by.Name, wfn: by.WFN, typ: by.Type, date: d.Date})
+		}
+	}
+	if len(result) == 0 && item.DeprecatedBy != "" {
+		wfn, err := naming.UnbindURI(item.DeprecatedBy)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to unbind %s", item.DeprecatedBy)
+		}
+		result = append(result, replacement{name: naming.BindToFS(wfn), wfn: wfn, date: item.DeprecationDate})
+	}
+	return result, nil
+}
+
+type resolver struct {
+	dict   *Dictionary
+	result *Resolution
+	// onPath holds the items on the chain being followed, done the items already resolved
+	onPath map[int]bool
+	done   map[int]bool
+}
+
+func (r *resolver) resolve(i int) error {
+	if r.done[i] {
+		return nil
+	}
+	item := r.dict.Items[i]
+	if !item.Deprecated && len(item.Deprecations) == 0 {
+		r.result.Current = append(r.result.Current, item)
+		r.done[i] = true
+		return nil
+	}
+
+	replacements, err := item.replacements()
+	if err != nil {
+		return err
+	}
+	if len(replacements) == 0 {
+		r.result.Removed = append(r.result.Removed, item)
+	}
+
+	r.onPath[i] = true
+	for _, rep := range replacements {
+		r.result.Chain = append(r.result.Chain, Step{From: item.FS, To: rep.name, Type: rep.typ, Date: rep.date})
+		targets := r.dict.find(rep.wfn)
+		if len(targets) == 0 {
+			r.result.Missing = append(r.result.Missing, rep.name)
+		}
+		for _, t := range targets {
+			if r.onPath[t] {
+				return errors.Wrapf(ErrDeprecationCycle, "%s is deprecated by %s", item.FS, rep.name)
+			}
+			if err = r.resolve(t); err != nil {
+				return err
+			}
+		}
+	}
+	r.onPath[i] = false
+	r.done[i] = true
+	return nil
+}
@@ -0,0 +1,130 @@
+package dictionary
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/knqyf263/go-cpe/naming"
+	"github.com/pkg/errors"
+)
+
+func TestDictionaryResolve(t *testing.T) {
+	f, err := os.Open("testdata/dictionary.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	dict, err := Parse(f)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	vectors := []struct {
+		fs      string
+		current []string
+		chain   []Step
+		missing []string
+		wantErr error
+	}{{
+		fs:      "cpe:2.3:a:apache:tomcat:7.0:*:*:*:*:*:*:*",
+		current: []string{"cpe:2.3:a:apache:tomcat:7.0:*:*:*:*:*:*:*"},
+	}, {
+		fs:      "cpe:2.3:a:apache:tomcat:8.0.0-rc1:*:*:*:*:*:*:*",
+		current: []string{"cpe:2.3:a:apache:tomcat:8.0.0:rc1:*:*:*:*:*:*"},
+		chain: []Step{{
+			From: "cpe:2.3:a:apache:tomcat:8.0.0-rc1:*:*:*:*:*:*:*",
+			To:   "cpe:2.3:a:apache:tomcat:8.0.0:rc1:*:*:*:*:*:*",
+			Type: NameCorrection,
+		}},
+	}, {
+		fs: "cpe:2.3:a:apache:tomcat_server:7.0:*:*:*:*:*:*:*",
+		current: []string{
+			"cpe:2.3:a:apache:tomcat:7.0:*:*:*:*:*:*:*",
+			"cpe:2.3:a:apache:tomcat:7.0:-:*:*:*:*:*:*",
+		},
+		chain: []Step{{
+			From: "cpe:2.3:a:apache:tomcat_server:7.0:*:*:*:*:*:*:*",
+			To:   "cpe:2.3:a:apache:tomcat_old:7.0:*:*:*:*:*:*:*",
+			Type: NameCorrection,
+		}, {
+			From: "cpe:2.3:a:apache:tomcat_old:7.0:*:*:*:*:*:*:*",
+			To:   "cpe:2.3:a:apache:tomcat:7.0:*:*:*:*:*:*:*",
+			Type: NameCorrection,
+		}, {
+			From: "cpe:2.3:a:apache:tomcat_old:7.0:*:*:*:*:*:*:*",
+			To:   "cpe:2.3:a:apache:tomcat:7.0:-:*:*:*:*:*:*",
+			Type: AdditionalInformation,
+		}},
+	}, {
+		fs:      "cpe:2.3:a:apache:struts:2.0:*:*:*:*:*:*:*",
+		wantErr: ErrNotFound,
+	},
+	}
+
+	for i, v := range vectors {
+		actual, err := dict.ResolveFS(v.fs)
+		if errors.Cause(err) != v.wantErr {
+			t.Errorf("test %d, Error: got %v, want %v", i, errors.Cause(err), v.wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		var current []string
+		for _, item := range actual.Current {
+			current = append(current, item.FS)
+		}
+		if !reflect.DeepEqual(current, v.current) {
+			t.Errorf("test %d, Current: %v, want %v", i, current, v.current)
+		}
+		var chain []Step
+		for _, step := range actual.Chain {
+			chain = append(chain, Step{From: step.From, To: step.To, Type: step.Type})
+		}
+		if !reflect.DeepEqual(chain, v.chain) {
+			t.Errorf("test %d, Chain: %v, want %v", i, chain, v.chain)
+		}
+		if !reflect.DeepEqual(actual.Missing, v.missing) {
+			t.Errorf("test %d, Missing: %v, want %v", i, actual.Missing, v.missing)
+		}
+	}
+}
+
+func TestDictionaryResolveEdgeCases(t *testing.T) {
+	item := func(uri, deprecatedBy string) Item {
+		wfn, _ := naming.UnbindURI(uri)
+		return Item{
+			Name:         uri,
+			FS:           naming.BindToFS(wfn),
+			WFN:          wfn,
+			Deprecated:   deprecatedBy != "" || uri == "cpe:/a:foo:removed",
+			DeprecatedBy: deprecatedBy,
+		}
+	}
+	dict := &Dictionary{Items: []Item{
+		item("cpe:/a:foo:a", "cpe:/a:foo:b"),
+		item("cpe:/a:foo:b", "cpe:/a:foo:a"),
+		item("cpe:/a:foo:c", "cpe:/a:foo:gone"),
+		item("cpe:/a:foo:removed", ""),
+	}}
+
+	if _, err := dict.ResolveFS("cpe:2.3:a:foo:a:*:*:*:*:*:*:*:*"); errors.Cause(err) != ErrDeprecationCycle {
+		t.Errorf("cycle: got %v, want %v", err, ErrDeprecationCycle)
+	}
+
+	r, err := dict.ResolveFS("cpe:2.3:a:foo:c:*:*:*:*:*:*:*:*")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(r.Missing, []string{"cpe:2.3:a:foo:gone:*:*:*:*:*:*:*:*"}) || len(r.Current) != 0 {
+		t.Errorf("missing: got %v", r)
+	}
+
+	r, err = dict.ResolveFS("cpe:2.3:a:foo:removed:*:*:*:*:*:*:*:*")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(r.Removed) != 1 || len(r.Current) != 0 {
+		t.Errorf("removed: got %v", r)
+	}
+}
@@ -0,0 +1,296 @@
+// Package dictionary implements the CPE Dictionary, as described in NIST IR 7697.
+package dictionary
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/knqyf263/go-cpe/common"
+	"github.com/knqyf263/go-cpe/naming"
+	"github.com/pkg/errors"
+)
+
+// DeprecationType is the reason a name was deprecated.
+type DeprecationType string
+
+const (
+	// NameCorrection : the name was corrected
+	NameCorrection DeprecationType = "NAME_CORRECTION"
+	// NameRemoval : the name was removed
+	NameRemoval DeprecationType = "NAME_REMOVAL"
+	// AdditionalInformation : the name was replaced by names with more information
+	AdditionalInformation DeprecationType = "ADDITIONAL_INFORMATION"
+
+	// DefaultLanguage is the language of titles and notes without a language tag
+	DefaultLanguage = "en-US"
+)
+
+// Dictionary represents a CPE Dictionary.
+type Dictionary struct {
+	Generator Generator
+	Items     []Item
+
+	mu    sync.Mutex
+	index *index
+}
+
+// Generator has information about the program and time that produced the dictionary.
+type Generator struct {
+	ProductName    string
+	ProductVersion string
+	SchemaVersion  string
+	Timestamp      time.Time
+}
+
+// Item is a single dictionary entry.
+type Item struct {
+	// Name is the CPE 2.2 URI of the entry
+	Name string
+	// FS is the CPE 2.3 formatted string of the entry
+	FS string
+	// WFN is FS unbound by naming.UnbindFS
+	WFN             common.WellFormedName
+	Titles          []Text
+	Notes           []Text
+	References      []Reference
+	Checks          []Check
+	Deprecated      bool
+	DeprecationDate time.Time
+	// DeprecatedBy is the CPE 2.2 URI replacing Name, if any
+	DeprecatedBy string
+	// Deprecations are the CPE 2.3 deprecations of FS
+	Deprecations []Deprecation
+}
+
+// Text is a localized text.
+type Text struct {
+	Lang  string
+	Value string
+}
+
+// Reference is a link to supporting information.
+type Reference struct {
+	Href string
+	Text string
+}
+
+// Check is a reference to a check that determines if the platform is present.
+type Check struct {
+	System string
+	Href   string
+	Value  string
+}
+
+// Deprecation records when and by which names an entry was deprecated.
+type Deprecation struct {
+	Date         time.Time
+	DeprecatedBy []DeprecatedBy
+}
+
+// DeprecatedBy is a name that replaces a deprecated name.
+type DeprecatedBy struct {
+	// Name is the CPE 2.3 formatted string of the replacement
+	Name string
+	// WFN is Name unbound by naming.UnbindFS
+	WFN  common.WellFormedName
+	Type DeprecationType
+}
+
+// Title returns the title in the given language, falling back to
+// DefaultLanguage and then to the first title.
+// @param lang language tag, compared case insensitively
+// @return title, or "" if the item has no title
+func (item Item) Title(lang string) string {
+	return localize(item.Titles, lang)
+}
+
+// Note returns the first note in the given language, with the same fallback as Title.
+func (item Item) Note(lang string) string {
+	return localize(item.Notes, lang)
+}
+
+func localize(texts []Text, lang string) string {
+	for _, l := range []string{lang, DefaultLanguage} {
+		for _, t := range texts {
+			if strings.EqualFold(t.Lang, l) {
+				return t.Value
+			}
+		}
+	}
+	if len(texts) > 0 {
+		return texts[0].Value
+	}
+	return ""
+}
+
+// Parse parses a whole CPE 2.3 dictionary into memory.
+// Use Decoder to process large dictionaries item by item.
+// @param r XML dictionary, optionally gzip-compressed
+// @return Dictionary, or an error if the XML or any name is invalid
+func Parse(r io.Reader) (*Dictionary, error) {
+	d, err := NewDecoder(r)
+	if err != nil {
+		return nil, err
+	}
+	defer d.Close()
+
+	dict := &Dictionary{}
+	for d.Next() {
+		dict.Items = append(dict.Items, d.Item())
+	}
+	if err = d.Err(); err != nil {
+		return nil, err
+	}
+	if err = d.SkipErr(); err != nil {
+		return nil, err
+	}
+	dict.Generator = d.Generator()
+	return dict, nil
+}
+
+type xmlGenerator struct {
+	ProductName    string `xml:"product_name"`
+	ProductVersion string `xml:"product_version"`
+	SchemaVersion  string `xml:"schema_version"`
+	Timestamp      string `xml:"timestamp"`
+}
+
+type xmlText struct {
+	Lang  string `xml:"lang,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlNotes struct {
+	Lang  string   `xml:"lang,attr"`
+	Notes []string `xml:"note"`
+}
+
+type xmlReference struct {
+	Href string `xml:"href,attr"`
+	Text string `xml:",chardata"`
+}
+
+type xmlCheck struct {
+	System string `xml:"system,attr"`
+	Href   string `xml:"href,attr"`
+	Value  string `xml:",chardata"`
+}
+
+type xmlCpeItem struct {
+	Name            string         `xml:"name,attr"`
+	Deprecated      bool           `xml:"deprecated,attr"`
+	DeprecationDate string         `xml:"deprecation_date,attr"`
+	DeprecatedBy    string         `xml:"deprecated_by,attr"`
+	Titles          []xmlText      `xml:"title"`
+	Notes           []xmlNotes     `xml:"notes"`
+	References      []xmlReference `xml:"references>reference"`
+	Checks          []xmlCheck     `xml:"check"`
+	Cpe23Item       xmlCpe23Item   `xml:"cpe23-item"`
+}
+
+type xmlCpe23Item struct {
+	Name         string           `xml:"name,attr"`
+	Deprecations []xmlDeprecation `xml:"deprecation"`
+}
+
+type xmlDeprecation struct {
+	Date         string            `xml:"date,attr"`
+	DeprecatedBy []xmlDeprecatedBy `xml:"deprecated-by"`
+}
+
+type xmlDeprecatedBy struct {
+	Name string `xml:"name,attr"`
+	Type string `xml:"type,attr"`
+}
+
+func (x xmlGenerator) convert() (g Generator, err error) {
+	g = Generator{
+		ProductName:    strings.TrimSpace(x.ProductName),
+		ProductVersion: strings.TrimSpace(x.ProductVersion),
+		SchemaVersion:  strings.TrimSpace(x.SchemaVersion),
+	}
+	if g.Timestamp, err = parseTime(x.Timestamp); err != nil {
+		return Generator{}, errors.Wrap(err, "Failed to parse generator timestamp")
+	}
+	return g, nil
+}
+
+func (x xmlCpeItem) convert() (item Item, err error) {
+	item = Item{
+		Name:         x.Name,
+		FS:           x.Cpe23Item.Name,
+		Deprecated:   x.Deprecated,
+		DeprecatedBy: x.DeprecatedBy,
+	}
+	if item.FS != "" {
+		if item.WFN, err = naming.UnbindFS(item.FS); err != nil {
+			return Item{}, errors.Wrapf(err, "Failed to unbind %s", item.FS)
+		}
+	}
+	if item.DeprecationDate, err = parseTime(x.DeprecationDate); err != nil {
+		return Item{}, errors.Wrapf(err, "Failed to parse deprecation date of %s", x.Name)
+	}
+	for _, t := range x.Titles {
+		item.Titles = append(item.Titles, Text{Lang: lang(t.Lang), Value: strings.TrimSpace(t.Value)})
+	}
+	for _, n := range x.Notes {
+		for _, note := range n.Notes {
+			item.Notes = append(item.Notes, Text{Lang: lang(n.Lang), Value: strings.TrimSpace(note)})
+		}
+	}
+	for _, r := range x.References {
+		item.References = append(item.References, Reference{Href: r.Href, Text: strings.TrimSpace(r.Text)})
+	}
+	for _, c := range x.Checks {
+		item.Checks = append(item.Checks, Check{System: c.System, Href: c.Href, Value: strings.TrimSpace(c.Value)})
+	}
+	for _, d := range x.Cpe23Item.Deprecations {
+		deprecation := Deprecation{}
+		if deprecation.Date, err = parseTime(d.Date); err != nil {
+			return Item{}, errors.Wrapf(err, "Failed to parse deprecation date of %s", item.FS)
+		}
+		for _, by := range d.DeprecatedBy {
+			wfn, err := naming.UnbindFS(by.Name)
+			if err != nil {
+				return Item{}, errors.Wrapf(err, "Failed to unbind %s", by.Name)
+			}
+			deprecation.DeprecatedBy = append(deprecation.DeprecatedBy, DeprecatedBy{
+				Name: by.Name,
+				WFN:  wfn,
+				Type: DeprecationType(by.Type),
+			})
+		}
+		item.Deprecations = append(item.Deprecations, deprecation)
+	}
+	return item, nil
+}
+
+func lang(l string) string {
+	if l == "" {
+		return DefaultLanguage
+	}
+	return l
+}
+
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+// parseTime parses the xsd:dateTime values used in dictionaries;
+// the time zone is optional. An empty string is the zero time.
+func parseTime(s string) (t time.Time, err error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err = time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
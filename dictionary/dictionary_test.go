@@ -0,0 +1,97 @@
+package dictionary
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/knqyf263/go-cpe/common"
+)
+
+func TestParse(t *testing.T) {
+	f, err := os.Open("testdata/dictionary.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	dict, err := Parse(f)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expectedGenerator := Generator{
+		ProductName:    "National Vulnerability Database (NVD)",
+		ProductVersion: "4.9",
+		SchemaVersion:  "2.3",
+		Timestamp:      time.Date(2021, 2, 2, 3, 50, 39, 613000000, time.UTC),
+	}
+	if !reflect.DeepEqual(dict.Generator, expectedGenerator) {
+		t.Errorf("Generator: got %v, want %v", dict.Generator, expectedGenerator)
+	}
+	if len(dict.Items) != 9 {
+		t.Fatalf("Items: got %d, want %d", len(dict.Items), 9)
+	}
+
+	item := dict.Items[0]
+	if item.Name != "cpe:/a:%240.99_kindle_books_project:%240.99_kindle_books:6::~~~android~~" {
+		t.Errorf("Name: got %s", item.Name)
+	}
+	if item.WFN.Get(common.AttributeVendor) != `\$0\.99_kindle_books_project` {
+		t.Errorf("WFN: got %v", item.WFN)
+	}
+	if item.Title("ja-JP") != "$0.99 Kindle Books プロジェクト" {
+		t.Errorf("Title(ja-JP): got %s", item.Title("ja-JP"))
+	}
+	if item.Title("fr-FR") != item.Title(DefaultLanguage) {
+		t.Errorf("Title(fr-FR): got %s", item.Title("fr-FR"))
+	}
+	if len(item.References) != 2 || item.References[0].Text != "Product information" {
+		t.Errorf("References: got %v", item.References)
+	}
+
+	item = dict.Items[1]
+	expectedNotes := []Text{{Lang: "en-US", Value: "first note"}, {Lang: "en-US", Value: "second note"}}
+	if !reflect.DeepEqual(item.Notes, expectedNotes) {
+		t.Errorf("Notes: got %v, want %v", item.Notes, expectedNotes)
+	}
+	expectedChecks := []Check{{
+		System: "http://oval.mitre.org/XMLSchema/oval-definitions-5",
+		Href:   "https://oval.example.com/oval.xml",
+		Value:  "oval:org.example:def:1",
+	}}
+	if !reflect.DeepEqual(item.Checks, expectedChecks) {
+		t.Errorf("Checks: got %v, want %v", item.Checks, expectedChecks)
+	}
+
+	item = dict.Items[2]
+	if !item.Deprecated || item.DeprecatedBy != "cpe:/a:apache:tomcat:8.0.0:rc1" {
+		t.Errorf("Deprecated: got %v, %s", item.Deprecated, item.DeprecatedBy)
+	}
+	if item.DeprecationDate.Year() != 2011 {
+		t.Errorf("DeprecationDate: got %v", item.DeprecationDate)
+	}
+	if len(item.Deprecations) != 1 || len(item.Deprecations[0].DeprecatedBy) != 1 {
+		t.Fatalf("Deprecations: got %v", item.Deprecations)
+	}
+	by := item.Deprecations[0].DeprecatedBy[0]
+	if by.Type != NameCorrection || by.WFN.Get(common.AttributeUpdate) != "rc1" {
+		t.Errorf("DeprecatedBy: got %v", by)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	vectors := []string{
+		`<cpe-list><cpe-item`,
+		`<cpe-list><cpe-item name="cpe:/a:foo"><cpe23-item name="cpe:2.3:a:foo"/></cpe-item></cpe-list>`,
+		`<cpe-list><generator><timestamp>yesterday</timestamp></generator></cpe-list>`,
+	}
+
+	for i, v := range vectors {
+		if _, err := Parse(strings.NewReader(v)); err == nil {
+			t.Errorf("test %d, expected error", i)
+		}
+	}
+}
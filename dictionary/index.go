@@ -0,0 +1,149 @@
+package dictionary
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/knqyf263/go-cpe/common"
+	"github.com/knqyf263/go-cpe/matching"
+)
+
+var indexedAttributes = []string{common.AttributePart, common.AttributeVendor, common.AttributeProduct}
+
+// index is a tree keyed by part, then vendor, then product,
+// whose leaves hold the positions of the items having those values.
+type index struct {
+	size int
+	root *indexNode
+	// values holds the attribute value each key was built from
+	values map[string]interface{}
+}
+
+type indexNode struct {
+	children map[string]*indexNode
+	items    []int
+}
+
+// indexKey returns the key of an attribute value. Strings are lowercased since
+// matching is case insensitive, and prefixed so they never collide with logical values.
+func indexKey(v interface{}) string {
+	if lv, ok := v.(common.LogicalValue); ok {
+		return lv.String()
+	}
+	if s, ok := v.(string); ok {
+		return "s:" + strings.ToLower(s)
+	}
+	return ""
+}
+
+func newIndex(items []Item) *index {
+	idx := &index{
+		size:   len(items),
+		root:   &indexNode{children: map[string]*indexNode{}},
+		values: map[string]interface{}{},
+	}
+	for i, item := range items {
+		if item.WFN == nil {
+			continue
+		}
+		node := idx.root
+		for _, attr := range indexedAttributes {
+			v := item.WFN.Get(attr)
+			key := indexKey(v)
+			idx.values[key] = v
+			child, ok := node.children[key]
+			if !ok {
+				child = &indexNode{children: map[string]*indexNode{}}
+				node.children[key] = child
+			}
+			node = child
+		}
+		node.items = append(node.items, i)
+	}
+	return idx
+}
+
+// candidates returns the children of a node that may be matched by the source value.
+func (idx *index) candidates(source interface{}, node *indexNode) []*indexNode {
+	var result []*indexNode
+	lv, logical := source.(common.LogicalValue)
+	s, _ := source.(string)
+	if (logical && lv.IsNA()) || (!logical && !common.ContainsWildcards(s)) {
+		// literals and NA only match themselves.
+		if child, ok := node.children[indexKey(source)]; ok {
+			result = append(result, child)
+		}
+		return result
+	}
+	for key, child := range node.children {
+		if logical {
+			// ANY matches everything.
+			result = append(result, child)
+			continue
+		}
+		r := matching.CompareValues(source, idx.values[key])
+		if r == matching.SUPERSET || r == matching.EQUAL {
+			result = append(result, child)
+		}
+	}
+	return result
+}
+
+// lookup returns the positions of the items whose part, vendor and product
+// may be matched by the source, in dictionary order.
+func (idx *index) lookup(source common.WellFormedName) []int {
+	nodes := []*indexNode{idx.root}
+	for _, attr := range indexedAttributes {
+		var next []*indexNode
+		for _, node := range nodes {
+			next = append(next, idx.candidates(source.Get(attr), node)...)
+		}
+		nodes = next
+	}
+	var result []int
+	for _, node := range nodes {
+		result = append(result, node.items...)
+	}
+	sort.Ints(result)
+	return result
+}
+
+// Match returns every dictionary item matched by the source,
+// i.e. the items for which matching.IsSuperset(source, item.WFN) holds,
+// in dictionary order.
+// An index on part, vendor and product is built on the first call and rebuilt
+// when items are appended; call Reindex after modifying items in place.
+// @param source Source WFN
+// @return matching items
+func (dict *Dictionary) Match(source common.WellFormedName) []Item {
+	var result []Item
+	for _, i := range dict.match(source) {
+		result = append(result, dict.Items[i])
+	}
+	return result
+}
+
+// match returns the positions of the items matched by the source.
+func (dict *Dictionary) match(source common.WellFormedName) []int {
+	dict.mu.Lock()
+	if dict.index == nil || dict.index.size != len(dict.Items) {
+		dict.index = newIndex(dict.Items)
+	}
+	idx := dict.index
+	dict.mu.Unlock()
+
+	var result []int
+	for _, i := range idx.lookup(source) {
+		if matching.IsSuperset(source, dict.Items[i].WFN) {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+// Reindex rebuilds the index used by Match.
+func (dict *Dictionary) Reindex() {
+	dict.mu.Lock()
+	defer dict.mu.Unlock()
+	dict.index = newIndex(dict.Items)
+}
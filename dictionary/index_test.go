@@ -0,0 +1,92 @@
+package dictionary
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/knqyf263/go-cpe/matching"
+	"github.com/knqyf263/go-cpe/naming"
+)
+
+func TestDictionaryMatch(t *testing.T) {
+	f, err := os.Open("testdata/dictionary.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	dict, err := Parse(f)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	vectors := []struct {
+		fs       string
+		expected []string
+	}{{
+		fs: "cpe:2.3:a:apache:tomcat:*:*:*:*:*:*:*:*",
+		expected: []string{
+			"cpe:/a:apache:tomcat:8.0.0:rc1",
+			"cpe:/a:apache:tomcat:8.0.0-rc1",
+			"cpe:/a:apache:tomcat:7.0",
+			"cpe:/a:apache:tomcat:7.0:-",
+		},
+	}, {
+		fs: "cpe:2.3:a:apache:tomcat*:7.0:*:*:*:*:*:*:*",
+		expected: []string{
+			"cpe:/a:apache:tomcat_server:7.0",
+			"cpe:/a:apache:tomcat_old:7.0",
+			"cpe:/a:apache:tomcat:7.0",
+			"cpe:/a:apache:tomcat:7.0:-",
+		},
+	}, {
+		fs: "cpe:2.3:a:APACHE:tomcat:7.0:-:*:*:*:*:*:*",
+		expected: []string{
+			"cpe:/a:apache:tomcat:7.0:-",
+		},
+	}, {
+		fs: "cpe:2.3:h:*:*:-:*:*:*:*:*:*:*",
+		expected: []string{
+			"cpe:/h:cisco:asa_5505",
+		},
+	}, {
+		fs: "cpe:2.3:o:*:windows_?:*:*:*:*:*:*:*:*",
+		expected: []string{
+			"cpe:/o:microsoft:windows_7::sp1",
+		},
+	}, {
+		fs:       "cpe:2.3:a:apache:struts:*:*:*:*:*:*:*:*",
+		expected: nil,
+	},
+	}
+
+	for i, v := range vectors {
+		source, err := naming.UnbindFS(v.fs)
+		if err != nil {
+			t.Fatalf("test %d, Unexpected error: %s", i, err)
+		}
+		var actual, naive []string
+		for _, item := range dict.Match(source) {
+			actual = append(actual, item.Name)
+		}
+		for _, item := range dict.Items {
+			if matching.IsSuperset(source, item.WFN) {
+				naive = append(naive, item.Name)
+			}
+		}
+		if !reflect.DeepEqual(actual, v.expected) {
+			t.Errorf("test %d, Result: %v, want %v", i, actual, v.expected)
+		}
+		if !reflect.DeepEqual(actual, naive) {
+			t.Errorf("test %d, Result: %v, naive loop %v", i, actual, naive)
+		}
+	}
+
+	// appended items are indexed
+	dict.Items = append(dict.Items, Item{Name: "cpe:/a:apache:struts:2.0"})
+	dict.Items[len(dict.Items)-1].WFN, _ = naming.UnbindURI("cpe:/a:apache:struts:2.0")
+	source, _ := naming.UnbindFS("cpe:2.3:a:apache:struts:*:*:*:*:*:*:*:*")
+	if actual := dict.Match(source); len(actual) != 1 {
+		t.Errorf("appended item: got %v", actual)
+	}
+}
@@ -2,4 +2,4 @@ module github.com/knqyf263/go-cpe
 
 go 1.15
 
-require github.com/pkg/errors v0.8.1
+require github.com/pkg/errors v0.9.1
@@ -0,0 +1,148 @@
+package matching
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"github.com/knqyf263/go-cpe/common"
+)
+
+// batchChunk is the number of targets a worker compares with a source at a time
+const batchChunk = 4096
+
+// BatchResult is the relation between a source and a target compared by a Batch.
+type BatchResult struct {
+	// SourceIdx is the position of the source in the slice, or the order in
+	// which it was received from the channel
+	SourceIdx int
+	TargetIdx int
+	Relation  Relation
+}
+
+// Batch compares many source WFNs with many target WFNs on a pool of goroutines.
+// The zero value uses GOMAXPROCS workers and sends every relation but DISJOINT.
+type Batch struct {
+	// Workers is the number of goroutines comparing names, or GOMAXPROCS if zero or less
+	Workers int
+	// Relations selects the relations sent, or every relation but DISJOINT if empty
+	Relations []Relation
+}
+
+// batchJob is a source to compare with a range of the targets.
+type batchJob struct {
+	sourceIdx int
+	matcher   *Matcher
+	lo, hi    int
+}
+
+// Compare compares each source with each target, as Relate does.
+// Results are sent in no particular order.  The channel is closed once every
+// pair is compared, or soon after ctx is done, which ctx.Err() tells apart.
+// The caller must receive until the channel is closed.
+// @param ctx Context cancelling the comparisons
+// @param sources Source WFNs
+// @param targets Target WFNs
+// @return channel of the selected results
+func (b Batch) Compare(ctx context.Context, sources, targets []common.WellFormedName) <-chan BatchResult {
+	ch := make(chan common.WellFormedName)
+	go func() {
+		defer close(ch)
+		for _, source := range sources {
+			select {
+			case ch <- source:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return b.CompareStream(ctx, ch, targets)
+}
+
+// CompareStream compares each source received from a channel with each target,
+// as Compare does.  Sources are compared while they are received, so the
+// sender must close the channel for the results to be closed.
+// @param ctx Context cancelling the comparisons
+// @param sources Source WFNs, numbered in the order they are received
+// @param targets Target WFNs
+// @return channel of the selected results
+func (b Batch) CompareStream(ctx context.Context, sources <-chan common.WellFormedName, targets []common.WellFormedName) <-chan BatchResult {
+	workers := b.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	var selected [UNDEFINED + 1]bool
+	for r := range selected {
+		selected[r] = len(b.Relations) == 0 && Relation(r) != DISJOINT
+	}
+	for _, r := range b.Relations {
+		if r >= DISJOINT && r <= UNDEFINED {
+			selected[r] = true
+		}
+	}
+
+	jobs := make(chan batchJob)
+	go func() {
+		defer close(jobs)
+		for idx := 0; ; idx++ {
+			var source common.WellFormedName
+			var ok bool
+			select {
+			case source, ok = <-sources:
+				if !ok {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+			m := Compile(source)
+			for lo := 0; lo < len(targets); lo += batchChunk {
+				hi := lo + batchChunk
+				if hi > len(targets) {
+					hi = len(targets)
+				}
+				select {
+				case jobs <- batchJob{sourceIdx: idx, matcher: m, lo: lo, hi: hi}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	results := make(chan BatchResult, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				job.run(ctx, targets, &selected, results)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	return results
+}
+
+// run sends the selected relations between the source and the targets of the
+// job, and returns early once ctx is done.
+func (job batchJob) run(ctx context.Context, targets []common.WellFormedName, selected *[UNDEFINED + 1]bool, results chan<- BatchResult) {
+	if ctx.Err() != nil {
+		return
+	}
+	for t := job.lo; t < job.hi; t++ {
+		r := job.matcher.Relate(targets[t])
+		if !selected[r] {
+			continue
+		}
+		select {
+		case results <- BatchResult{SourceIdx: job.sourceIdx, TargetIdx: t, Relation: r}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package matching
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/knqyf263/go-cpe/common"
+	"github.com/knqyf263/go-cpe/naming"
+)
+
+func unbindAll(t testing.TB, names []string) []common.WellFormedName {
+	var wfns []common.WellFormedName
+	for _, fs := range names {
+		wfn, err := naming.UnbindFS(fs)
+		if err != nil {
+			t.Fatalf("UnbindFS(%q): %v", fs, err)
+		}
+		wfns = append(wfns, wfn)
+	}
+	return wfns
+}
+
+func collect(results <-chan BatchResult) []BatchResult {
+	var all []BatchResult
+	for r := range results {
+		all = append(all, r)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].SourceIdx != all[j].SourceIdx {
+			return all[i].SourceIdx < all[j].SourceIdx
+		}
+		return all[i].TargetIdx < all[j].TargetIdx
+	})
+	return all
+}
+
+func TestBatchCompare(t *testing.T) {
+	sources := unbindAll(t, []string{
+		`cpe:2.3:a:apache:tomcat:*:*:*:*:*:*:*:*`,
+		`cpe:2.3:a:apache:tomcat:8.0.*:*:*:*:*:*:*:*`,
+		`cpe:2.3:o:microsoft:windows_7:-:sp1:*:*:*:*:*:*`,
+		`cpe:2.3:a:*:*:*:*:*:*:*:*:*:*`,
+	})
+	targets := unbindAll(t, []string{
+		`cpe:2.3:a:apache:tomcat:8.0.1:*:*:*:*:*:*:*`,
+		`cpe:2.3:a:apache:tomcat:7.0:-:*:*:*:*:*:*`,
+		`cpe:2.3:a:apache:*:*:*:*:*:*:*:*:*`,
+		`cpe:2.3:o:microsoft:windows_7:-:sp1:*:*:*:*:*:*`,
+		`cpe:2.3:a:apache:tomcat:8.*:*:*:*:*:*:*:*`,
+	})
+
+	vectors := []struct {
+		batch    Batch
+		selected []Relation
+	}{
+		{batch: Batch{}, selected: []Relation{SUBSET, SUPERSET, EQUAL, UNDEFINED}},
+		{batch: Batch{Workers: 1}, selected: []Relation{SUBSET, SUPERSET, EQUAL, UNDEFINED}},
+		{batch: Batch{Workers: 3, Relations: []Relation{SUPERSET, EQUAL}}, selected: []Relation{SUPERSET, EQUAL}},
+		{batch: Batch{Relations: []Relation{DISJOINT}}, selected: []Relation{DISJOINT}},
+	}
+	for i, v := range vectors {
+		var expected []BatchResult
+		for s, source := range sources {
+			for t, target := range targets {
+				r := Relate(source, target)
+				for _, sel := range v.selected {
+					if r == sel {
+						expected = append(expected, BatchResult{SourceIdx: s, TargetIdx: t, Relation: r})
+					}
+				}
+			}
+		}
+
+		actual := collect(v.batch.Compare(context.Background(), sources, targets))
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("test %d, Compare: got %v, want %v", i, actual, expected)
+		}
+
+		ch := make(chan common.WellFormedName)
+		go func() {
+			for _, source := range sources {
+				ch <- source
+			}
+			close(ch)
+		}()
+		actual = collect(v.batch.CompareStream(context.Background(), ch, targets))
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("test %d, CompareStream: got %v, want %v", i, actual, expected)
+		}
+	}
+
+	if actual := collect(Batch{}.Compare(context.Background(), sources, nil)); actual != nil {
+		t.Errorf("no targets: got %v", actual)
+	}
+}
+
+func TestBatchCancel(t *testing.T) {
+	sources := unbindAll(t, []string{`cpe:2.3:a:*:*:*:*:*:*:*:*:*:*`, `cpe:2.3:a:foo:*:*:*:*:*:*:*:*:*`})
+	target := unbindAll(t, []string{`cpe:2.3:a:foo:bar:*:*:*:*:*:*:*:*`})[0]
+	var targets []common.WellFormedName
+	for i := 0; i < 3*batchChunk; i++ {
+		targets = append(targets, target)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	n := 0
+	for range (Batch{Workers: 2}).Compare(ctx, sources, targets) {
+		n++
+		if n == 10 {
+			cancel()
+		}
+	}
+	if ctx.Err() == nil {
+		t.Errorf("expected the context to be done")
+	}
+	if n >= len(sources)*len(targets) {
+		t.Errorf("got %d results after cancel, want fewer than %d", n, len(sources)*len(targets))
+	}
+
+	// a cancelled stream is closed even if the sources channel is not
+	ctx, cancel = context.WithCancel(context.Background())
+	results := Batch{}.CompareStream(ctx, make(chan common.WellFormedName), targets)
+	cancel()
+	for range results {
+	}
+}
+
+func BenchmarkBatch(b *testing.B) {
+	var sources, targets []common.WellFormedName
+	for i := 0; i < 100; i++ {
+		sources = append(sources, common.WellFormedName{
+			"part": "a", "vendor": fmt.Sprintf("vendor%d", i%10), "product": fmt.Sprintf("product%d", i), "version": `1\.*`,
+		})
+	}
+	for i := 0; i < 10000; i++ {
+		targets = append(targets, common.WellFormedName{
+			"part": "a", "vendor": fmt.Sprintf("vendor%d", i%10), "product": fmt.Sprintf("product%d", i%100), "version": fmt.Sprintf(`%d\.%d`, i%3, i),
+		})
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for range (Batch{}).Compare(context.Background(), sources, targets) {
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package matching
+
+import (
+	"github.com/knqyf263/go-cpe/common"
+	"github.com/pkg/errors"
+)
+
+// Covers tests whether the sets of the patterns cover the set of the target,
+// i.e. whether every name the target is a superset of is matched by one of
+// the patterns, with the sets Intersect defines.  Several patterns may cover
+// a target that none of them covers alone, e.g. NA, "?" and "?*" cover ANY.
+// Patterns disjoint from the target are dropped first.  Patterns holding a
+// value that is not a valid string are not relied upon.
+// @param patterns WFNs
+// @param target WFN
+// @return true if the patterns cover the target, false otherwise or if a
+// value of the target is not a valid string
+func Covers(patterns []common.WellFormedName, target common.WellFormedName) bool {
+	var sets [11]valueSet
+	for i, attr := range attributes {
+		s, err := newValueSet(target.Get(attr))
+		if err != nil {
+			return false
+		}
+		sets[i] = s
+	}
+
+	var restricted [][11]valueSet
+	for _, p := range patterns {
+		ps, err := restrict(p, &sets)
+		switch {
+		case err != nil:
+			continue
+		case ps == nil:
+			return true
+		}
+		restricted = append(restricted, *ps)
+	}
+	if len(restricted) == 0 {
+		return false
+	}
+
+	// A name outside every pattern is sought: for each attribute, the
+	// patterns a value of the target is not in are found, and the target is
+	// covered unless a value can be chosen for each attribute so that every
+	// pattern misses one of them.
+	var options [11][]bitset
+	for i := range sets {
+		var values []valueSet
+		for _, ps := range restricted {
+			values = append(values, ps[i])
+		}
+		options[i] = misses(sets[i], values)
+	}
+	return !escapes(options[:], newBitset(0), len(restricted), map[string]bool{})
+}
+
+// restrict intersects the values of a pattern with those of the target.
+// @return the values of the pattern, nil if they hold every value of the
+// target, or an error if the pattern is disjoint from the target or holds
+// an invalid value
+func restrict(pattern common.WellFormedName, target *[11]valueSet) (*[11]valueSet, error) {
+	var ps [11]valueSet
+	holds := true
+	for i, attr := range attributes {
+		s, err := newValueSet(pattern.Get(attr))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := s.intersect(target[i]); errors.Cause(err) == ErrEmptyIntersection {
+			return nil, err
+		}
+		ps[i] = s
+		holds = holds && s.contains(target[i])
+	}
+	if holds {
+		return nil, nil
+	}
+	return &ps, nil
+}
+
+// misses returns, for the values held by a set, the sets of patterns that do
+// not hold them, keeping only those not included in another.
+//
+// Replacing the characters around the body of a string with characters of no
+// body only drops it from patterns, so the strings x+body+y made of such
+// characters are enough, and the patterns only tell apart lengths of x and y
+// up to the largest of their bounds.
+func misses(s valueSet, patterns []valueSet) []bitset {
+	var found []bitset
+	addMisses := func(holds func(valueSet) bool) {
+		b := newBitset(len(patterns))
+		for i, p := range patterns {
+			if !holds(p) {
+				b.set(i)
+			}
+		}
+		kept := found[:0]
+		for _, f := range found {
+			if f.includes(b) {
+				return
+			}
+			if !b.includes(f) {
+				kept = append(kept, f)
+			}
+		}
+		found = append(kept, b)
+	}
+
+	if s.na {
+		addMisses(func(p valueSet) bool { return p.na })
+	}
+	if !s.strings {
+		return found
+	}
+	sh := s.shape
+	limit := sh.min + 1
+	for _, p := range patterns {
+		for _, v := range []int{p.shape.left, p.shape.right, add(p.shape.left, p.shape.right), p.shape.min} {
+			if v != unbounded {
+				limit = maxInt(limit, v+1)
+			}
+		}
+	}
+	for x := 0; x <= minInt(sh.left, limit); x++ {
+		for y := 0; y <= minInt(sh.right, limit); y++ {
+			if x+len(sh.body)+y < sh.min {
+				continue
+			}
+			addMisses(func(p valueSet) bool { return p.strings && p.shape.holds(x, sh.body, y) })
+		}
+	}
+	return found
+}
+
+// holds tests whether the shape holds a string made of x characters of no
+// body, the given body, and y characters of no body.
+func (sh shape) holds(x int, body []unit, y int) bool {
+	n := x + len(body) + y
+	if len(sh.body) == 0 {
+		return n >= sh.min && n <= sh.maxLen()
+	}
+	for q := indexUnits(body, sh.body, 0); q != -1; q = indexUnits(body, sh.body, q+1) {
+		if x+q <= sh.left && y+len(body)-q-len(sh.body) <= sh.right {
+			return true
+		}
+	}
+	return false
+}
+
+// escapes tests whether a miss can be chosen for each remaining attribute so
+// that, with the patterns already missed, every pattern is missed.
+func escapes(options [][]bitset, missed bitset, n int, seen map[string]bool) bool {
+	if missed.count() == n {
+		return true
+	}
+	if len(options) == 0 {
+		return false
+	}
+	key := string(rune(len(options))) + missed.key()
+	if r, ok := seen[key]; ok {
+		return r
+	}
+	r := false
+	for _, b := range options[0] {
+		if escapes(options[1:], missed.or(b), n, seen) {
+			r = true
+			break
+		}
+	}
+	seen[key] = r
+	return r
+}
+
+// bitset is a set of pattern indexes.
+type bitset []uint64
+
+func newBitset(n int) bitset {
+	return make(bitset, (n+63)/64)
+}
+
+func (b bitset) set(i int) {
+	b[i/64] |= 1 << uint(i%64)
+}
+
+func (b bitset) or(c bitset) bitset {
+	r := make(bitset, maxInt(len(b), len(c)))
+	copy(r, c)
+	for i, w := range b {
+		r[i] |= w
+	}
+	return r
+}
+
+// includes tests whether b holds every index of c
+func (b bitset) includes(c bitset) bool {
+	for i, w := range c {
+		if i >= len(b) && w != 0 || i < len(b) && w&^b[i] != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (b bitset) count() int {
+	n := 0
+	for _, w := range b {
+		for ; w != 0; w &= w - 1 {
+			n++
+		}
+	}
+	return n
+}
+
+func (b bitset) key() string {
+	s := make([]byte, 0, 8*len(b))
+	for _, w := range b {
+		for i := uint(0); i < 64; i += 8 {
+			s = append(s, byte(w>>i))
+		}
+	}
+	return string(s)
+}
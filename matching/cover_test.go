@@ -0,0 +1,140 @@
+package matching
+
+import (
+	"testing"
+
+	"github.com/knqyf263/go-cpe/common"
+	"github.com/knqyf263/go-cpe/naming"
+)
+
+func TestCovers(t *testing.T) {
+	vectors := []struct {
+		patterns []string
+		target   string
+		expected bool
+	}{{
+		patterns: []string{`cpe:2.3:a:acme:*:*:*:*:*:*:*:*:*`},
+		target:   `cpe:2.3:a:acme:foo:1.0:*:*:*:*:*:*:*`,
+		expected: true,
+	}, {
+		patterns: []string{`cpe:2.3:a:acme:foo:1.*:*:*:*:*:*:*:*`, `cpe:2.3:a:acme:foo:2.*:*:*:*:*:*:*:*`},
+		target:   `cpe:2.3:a:acme:foo:*:*:*:*:*:*:*:*`,
+		expected: false,
+	}, {
+		// NA, every string of at most one character, and every longer string
+		patterns: []string{`cpe:2.3:a:acme:foo:-:*:*:*:*:*:*:*`, `cpe:2.3:a:acme:foo:?:*:*:*:*:*:*:*`, `cpe:2.3:a:acme:foo:?*:*:*:*:*:*:*:*`},
+		target:   `cpe:2.3:a:acme:foo:*:*:*:*:*:*:*:*`,
+		expected: true,
+	}, {
+		patterns: []string{`cpe:2.3:a:acme:foo:?:*:*:*:*:*:*:*`, `cpe:2.3:a:acme:foo:?*:*:*:*:*:*:*:*`},
+		target:   `cpe:2.3:a:acme:foo:*:*:*:*:*:*:*:*`,
+		expected: false,
+	}, {
+		// the versions are covered for NA and strings of at most one character,
+		// and the updates for the longer versions
+		patterns: []string{
+			`cpe:2.3:a:acme:foo:-:*:*:*:*:*:*:*`,
+			`cpe:2.3:a:acme:foo:?:*:*:*:*:*:*:*`,
+			`cpe:2.3:a:acme:foo:?*:-:*:*:*:*:*:*`,
+			`cpe:2.3:a:acme:foo:?*:?:*:*:*:*:*:*`,
+			`cpe:2.3:a:acme:foo:?*:?*:*:*:*:*:*:*`,
+		},
+		target:   `cpe:2.3:a:acme:foo:*:*:*:*:*:*:*:*`,
+		expected: true,
+	}, {
+		patterns: []string{
+			`cpe:2.3:a:acme:foo:-:*:*:*:*:*:*:*`,
+			`cpe:2.3:a:acme:foo:?:*:*:*:*:*:*:*`,
+			`cpe:2.3:a:acme:foo:?*:?:*:*:*:*:*:*`,
+			`cpe:2.3:a:acme:foo:?*:?*:*:*:*:*:*:*`,
+		},
+		target:   `cpe:2.3:a:acme:foo:*:*:*:*:*:*:*:*`,
+		expected: false,
+	}, {
+		patterns: []string{
+			`cpe:2.3:a:acme:foo:1\.?:*:*:*:*:*:*:*`,
+			`cpe:2.3:a:acme:foo:1\.*:beta:*:*:*:*:*:*`,
+		},
+		target:   `cpe:2.3:a:acme:foo:1\.??:*:*:*:*:*:*:*`,
+		expected: false,
+	}, {
+		patterns: []string{`cpe:2.3:a:acme:foo:1\.0:*:*:*:*:*:*:*`},
+		target:   `cpe:2.3:a:acme:foo:1\.01:*:*:*:*:*:*:*`,
+		expected: false,
+	}, {
+		patterns: []string{`cpe:2.3:a:acme:foo:1\.0:*:*:*:*:*:*:*`, `cpe:2.3:a:acme:foo:1\.0?:*:*:*:*:*:*:*`},
+		target:   `cpe:2.3:a:acme:foo:1\.0*:*:*:*:*:*:*:*`,
+		expected: false,
+	}, {
+		patterns: []string{`cpe:2.3:a:acme:foo:1\.0?:*:*:*:*:*:*:*`},
+		target:   `cpe:2.3:a:acme:foo:1\.0x:*:*:*:*:*:*:*`,
+		expected: true,
+	}, {
+		patterns: []string{`cpe:2.3:o:acme:foo:*:*:*:*:*:*:*:*`},
+		target:   `cpe:2.3:a:acme:foo:*:*:*:*:*:*:*:*`,
+		expected: false,
+	}, {
+		patterns: nil,
+		target:   `cpe:2.3:a:acme:foo:*:*:*:*:*:*:*:*`,
+		expected: false,
+	},
+	}
+
+	for i, v := range vectors {
+		var patterns []common.WellFormedName
+		for _, s := range v.patterns {
+			p, err := naming.UnbindFS(s)
+			if err != nil {
+				t.Fatalf("test %d, UnbindFS: %v", i, err)
+			}
+			patterns = append(patterns, p)
+		}
+		target, err := naming.UnbindFS(v.target)
+		if err != nil {
+			t.Fatalf("test %d, UnbindFS: %v", i, err)
+		}
+		if actual := Covers(patterns, target); actual != v.expected {
+			t.Errorf("test %d, Covers: got %v, want %v", i, actual, v.expected)
+		}
+	}
+}
+
+func TestCoversBruteForce(t *testing.T) {
+	// values with at most one ? at an end, so that testNames holds a name
+	// outside the patterns whenever there is one
+	var values []interface{}
+	for _, left := range []string{"", "?", "*"} {
+		for _, body := range []string{"a", "b", "aa", "ab"} {
+			for _, right := range []string{"", "?", "*"} {
+				values = append(values, left+body+right)
+			}
+		}
+	}
+	values = append(values, "", "?", "??", "?*", "*?", common.LogicalValue{Any: true}, common.LogicalValue{Na: true})
+	names := testNames()
+	members := make([][]bool, len(values))
+	for i, v := range values {
+		members[i] = memberships(v, names)
+	}
+	wfn := func(v interface{}) common.WellFormedName {
+		return common.WellFormedName{common.AttributeVendor: v}
+	}
+
+	for target := range values {
+		for i := range values {
+			for j := i; j < len(values); j++ {
+				expected := true
+				for k := range names {
+					if members[target][k] && !members[i][k] && !members[j][k] {
+						expected = false
+						break
+					}
+				}
+				patterns := []common.WellFormedName{wfn(values[i]), wfn(values[j])}
+				if actual := Covers(patterns, wfn(values[target])); actual != expected {
+					t.Fatalf("Covers(%v, %v, %v): got %v, want %v", values[i], values[j], values[target], actual, expected)
+				}
+			}
+		}
+	}
+}
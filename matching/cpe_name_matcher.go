@@ -22,6 +22,21 @@ const (
 	UNDEFINED
 )
 
+// String returns string representation of the Relation
+func (r Relation) String() string {
+	switch r {
+	case DISJOINT:
+		return "DISJOINT"
+	case SUBSET:
+		return "SUBSET"
+	case SUPERSET:
+		return "SUPERSET"
+	case EQUAL:
+		return "EQUAL"
+	}
+	return "UNDEFINED"
+}
+
 // IsDisjoint tests two Well Formed Names for disjointness.
 // @param source Source WFN
 // @param target Target WFN
@@ -85,6 +100,15 @@ func IsSuperset(source, target common.WellFormedName) bool {
 	return true
 }
 
+// Relate compares two Well Formed Names as a whole.
+// @param source Source WFN
+// @param target Target WFN
+// @return DISJOINT if IsDisjoint holds, else EQUAL if IsEqual holds, else
+// SUPERSET if IsSuperset holds, else SUBSET if IsSubset holds, else UNDEFINED
+func Relate(source, target common.WellFormedName) Relation {
+	return Compile(source).Relate(target)
+}
+
 // CompareWFNs compares each attribute value pair in two Well Formed Names.
 // @param source Source WFN
 // @param target Target WFN
@@ -105,57 +129,22 @@ func CompareWFNs(source, target common.WellFormedName) map[string]Relation {
 	return result
 }
 
+// CompareValues compares a source attribute value to a target attribute value,
+// as CompareWFNs does for each attribute.
+// @param source Source attribute value, a string or a LogicalValue
+// @param target Target attribute value, a string or a LogicalValue
+// @return The relation between the two attribute values.
+func CompareValues(source, target interface{}) Relation {
+	return compare(source, target)
+}
+
 // Compares an attribute value pair.
 // @param source Source attribute value.
 // @param target Target attribute value.
 // @return The relation between the two attribute values.
 func compare(source, target interface{}) Relation {
-	var s, t string
-	var ok bool
-
-	// matching is case insensitive, convert strings to lowercase.
-	if s, ok = source.(string); ok {
-		s = strings.ToLower(s)
-	}
-	if t, ok = target.(string); ok {
-		t = strings.ToLower(t)
-	}
-	// Unquoted wildcard characters yield an undefined result.
-	if common.ContainsWildcards(t) {
-		return UNDEFINED
-	}
-
-	// If source and target values are equal, then result is equal.
-	if source == target {
-		return EQUAL
-	}
-
-	// Check to see if source or target are Logical Values.
-	var lvSource, lvTarget common.LogicalValue
-	if lv, ok := source.(common.LogicalValue); ok {
-		lvSource = lv
-	}
-	if lv, ok := target.(common.LogicalValue); ok {
-		lvTarget = lv
-	}
-	// If source value is ANY, result is a superset.
-	if lvSource.IsANY() {
-		return SUPERSET
-	}
-	// If target value is ANY, result is a subset.
-	if lvTarget.IsANY() {
-		return SUBSET
-	}
-	// If source or target is NA, result is disjoint.
-	if lvSource.IsNA() {
-		return DISJOINT
-	}
-	// if (lvTarget != null) {
-	if lvTarget.IsNA() {
-		return DISJOINT
-	}
-	// only Strings will get to this point, not LogicalValues
-	return compareStrings(s, t)
+	v := compileValue(source)
+	return v.compare(target)
 }
 
 // compareStrings compares a source string to a target string, and addresses the condition
@@ -167,51 +156,122 @@ func compare(source, target interface{}) Relation {
 //
 // @return Relation between source and target Strings.
 func compareStrings(source, target string) Relation {
-	var start, begins, ends, index, leftover, escapes int
-	end := len(source)
+	p := compilePattern(source)
+	return p.match(target)
+}
+
+// patternKind is enumeration for the shapes of a source string.
+type patternKind int
+
+const (
+	// patternEmpty : an empty string, which matches no target
+	patternEmpty patternKind = iota
+	// patternQuestions : only ? (e.g. "???")
+	patternQuestions
+	// patternWildcards : only wildcards, with at least one * (e.g. "*", "??*")
+	patternWildcards
+	// patternBody : a body with optional leading and trailing wildcards
+	patternBody
+)
+
+// pattern is a source string pre-processed by compilePattern, so that it
+// can be matched against many target strings.
+type pattern struct {
+	kind patternKind
+	// body is the source without its leading and trailing wildcards
+	body string
+	// begins is the number of leading ?, or -1 for a leading *
+	begins int
+	// ends is the number of trailing ?, or -1 for a trailing *
+	ends int
+	// length is the most characters a target may have for patternQuestions,
+	// and the least for patternWildcards
+	length int
+}
+
+// compilePattern finds the leading and trailing wildcards of a source string.
+// @param source lowercase source string
+// @return pattern to match targets with
+func compilePattern(source string) pattern {
+	var p pattern
+	start, end := 0, len(source)
+	if end == 0 {
+		// An empty source only equals an empty target, which is handled by the caller.
+		return p
+	}
 
 	if source[0] == '*' {
 		start = 1
-		begins = -1
+		p.begins = -1
 	} else {
 		for start < len(source) && source[start] == '?' {
 			start++
-			begins++
+			p.begins++
 		}
 	}
 
 	if source[end-1] == '*' && IsEvenWildcards(source, end-1) {
 		end--
-		ends = -1
+		p.ends = -1
 	} else {
 		for end > 0 && source[end-1] == '?' && IsEvenWildcards(source, end-1) {
 			end--
-			ends++
+			p.ends++
 		}
 	}
 
-	// only ? (e.g. "???")
-	if strings.Trim(source, "?") == "" {
-		if len(source) >= common.LengthWithEscapeCharacters(target) {
+	switch {
+	case strings.Trim(source, "?") == "":
+		p.kind = patternQuestions
+		p.length = len(source)
+	case start >= end:
+		p.kind = patternWildcards
+		if p.begins > 0 {
+			p.length += p.begins
+		}
+		if p.ends > 0 {
+			p.length += p.ends
+		}
+	default:
+		p.kind = patternBody
+		p.body = source[start:end]
+	}
+	return p
+}
+
+// match compares the source string of the pattern to a target string.
+// @param target lowercase target string
+// @return Relation between source and target Strings.
+func (p *pattern) match(target string) Relation {
+	switch p.kind {
+	case patternEmpty:
+		return DISJOINT
+	case patternQuestions:
+		if p.length >= common.LengthWithEscapeCharacters(target) {
+			return SUPERSET
+		}
+		return DISJOINT
+	case patternWildcards:
+		if common.LengthWithEscapeCharacters(target) >= p.length {
 			return SUPERSET
 		}
 		return DISJOINT
 	}
-	source = source[start:end]
-	index = -1
-	leftover = len(target)
+
+	index := -1
+	leftover := len(target)
 	for leftover > 0 {
-		index = common.IndexOf(target, source, index+1)
+		index = common.IndexOf(target, p.body, index+1)
 		if index == -1 {
 			break
 		}
-		escapes = common.CountEscapeCharacters(target[:index])
-		if index > 0 && begins != -1 && begins < (index-escapes) {
+		// wildcards count characters, not bytes
+		if index > 0 && p.begins != -1 && p.begins < common.LengthWithEscapeCharacters(target[:index]) {
 			break
 		}
-		escapes = common.CountEscapeCharacters(target[index+1:])
-		leftover = len(target) - index - escapes - len(source)
-		if leftover > 0 && ends != -1 && leftover > ends {
+		// only the characters after the body are left over
+		leftover = common.LengthWithEscapeCharacters(target[index+len(p.body):])
+		if leftover > 0 && p.ends != -1 && leftover > p.ends {
 			continue
 		}
 		return SUPERSET
@@ -219,6 +279,30 @@ func compareStrings(source, target string) Relation {
 	return DISJOINT
 }
 
+// rule returns the rule by which the pattern gave a relation.
+// @param r Relation returned by match
+// @return Rule
+func (p *pattern) rule(r Relation) Rule {
+	switch p.kind {
+	case patternEmpty:
+		return RuleEmptySource
+	case patternQuestions, patternWildcards:
+		return RuleWildcardLength
+	}
+	switch {
+	case p.begins == 0 && p.ends == 0:
+		if r == SUPERSET {
+			return RuleCaseInsensitive
+		}
+		return RuleStringMismatch
+	case p.begins == 0:
+		return RuleWildcardPrefix
+	case p.ends == 0:
+		return RuleWildcardSuffix
+	}
+	return RuleWildcardInfix
+}
+
 // IsEvenWildcards searches a string for the backslash character
 // @param str string to search in
 // @param idx end index
@@ -1,6 +1,9 @@
 package matching
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/knqyf263/go-cpe/common"
@@ -382,6 +385,42 @@ func TestMatcher(t *testing.T) {
 	}
 }
 
+func TestCompareValues(t *testing.T) {
+	any, _ := common.NewLogicalValue("ANY")
+	na, _ := common.NewLogicalValue("NA")
+	vectors := []struct {
+		source   interface{}
+		target   interface{}
+		expected Relation
+	}{
+		{source: any, target: "foo", expected: SUPERSET},
+		{source: "foo", target: any, expected: SUBSET},
+		{source: na, target: na, expected: EQUAL},
+		{source: na, target: "foo", expected: DISJOINT},
+		{source: "foo*", target: "foobar", expected: SUPERSET},
+		{source: "foo", target: "foo*", expected: UNDEFINED},
+		{source: "", target: "foo", expected: DISJOINT},
+		{source: "*", target: "foo", expected: SUPERSET},
+		{source: "??*", target: "f", expected: DISJOINT},
+		{source: "*??", target: "foo", expected: SUPERSET},
+		{source: "?ller", target: `\üller`, expected: SUPERSET},
+		{source: "??", target: `\日\本`, expected: SUPERSET},
+		{source: "m\\ü*", target: `m\üller`, expected: SUPERSET},
+		{source: `1\.0`, target: `1\.01`, expected: DISJOINT},
+		{source: `1\.0`, target: `1\.0x`, expected: DISJOINT},
+		{source: `1\.0?`, target: `1\.0\-`, expected: SUPERSET},
+		{source: `1\.0?`, target: `1\.0\-1`, expected: DISJOINT},
+		{source: `\-a`, target: `\-ax`, expected: DISJOINT},
+	}
+
+	for i, v := range vectors {
+		actual := CompareValues(v.source, v.target)
+		if actual != v.expected {
+			t.Errorf("test %d, Result: got %v, want %v", i, actual, v.expected)
+		}
+	}
+}
+
 func TestIsEvenWildcards(t *testing.T) {
 	vectors := []struct {
 		str      string
@@ -421,3 +460,50 @@ func TestIsEvenWildcards(t *testing.T) {
 		}
 	}
 }
+
+// TestCompareValuesOracle compares the values, whose bodies hold quoted
+// characters, with a regular expression matching the unquoted characters.
+func TestCompareValuesOracle(t *testing.T) {
+	sources := []string{`1\.0`, `1\.0?`, `1\.0??`, `?\.0`, `*\.0`, `1\.*`, `a\-b`, `\-a`, `\-a?`, `?a\-`, `*\-\-*`}
+	units := []string{"1", "0", "a", "b", `\.`, `\-`}
+	// every target of at most three units
+	targets := []string{""}
+	level := []string{""}
+	for n := 0; n < 3; n++ {
+		var next []string
+		for _, t := range level {
+			for _, u := range units {
+				next = append(next, t+u)
+			}
+		}
+		targets = append(targets, next...)
+		level = next
+	}
+
+	for _, source := range sources {
+		lead := source[:len(source)-len(strings.TrimLeft(source, "*?"))]
+		trail := source[len(strings.TrimRight(source, "*?")):]
+		expr := "^" + wildcardExpr(lead) + regexp.QuoteMeta(common.Unquote(strings.Trim(source, "*?"))) + wildcardExpr(trail) + "$"
+		re := regexp.MustCompile(expr)
+		for _, target := range targets {
+			expected := DISJOINT
+			if re.MatchString(common.Unquote(target)) {
+				expected = SUPERSET
+			}
+			if source == target {
+				expected = EQUAL
+			}
+			if actual := CompareValues(source, target); actual != expected {
+				t.Errorf("CompareValues(%q, %q): got %v, want %v", source, target, actual, expected)
+			}
+		}
+	}
+}
+
+// wildcardExpr returns the regular expression of the wildcards at an end of a value
+func wildcardExpr(w string) string {
+	if w == "*" {
+		return ".*"
+	}
+	return fmt.Sprintf(".{0,%d}", len(w))
+}
@@ -0,0 +1,197 @@
+package matching
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/knqyf263/go-cpe/common"
+)
+
+// Rule is enumeration for the rules of the matching algorithm that decide
+// the relation of an attribute value pair.
+type Rule int
+
+const (
+	// RuleEqualValues : the values are identical
+	RuleEqualValues Rule = iota
+	// RuleTargetWildcards : the target contains unquoted wildcards, so the relation is undefined
+	RuleTargetWildcards
+	// RuleANYSuperset : the source is ANY, which is a superset of any other value
+	RuleANYSuperset
+	// RuleANYSubset : the target is ANY, which is a superset of any other value
+	RuleANYSubset
+	// RuleNADisjoint : the source or the target is NA, which is disjoint from any other value
+	RuleNADisjoint
+	// RuleEmptySource : the source is an empty string, which only equals an empty target
+	RuleEmptySource
+	// RuleCaseInsensitive : the strings only differ in case, so the source is a superset
+	RuleCaseInsensitive
+	// RuleStringMismatch : the strings differ
+	RuleStringMismatch
+	// RuleWildcardLength : the source only holds wildcards, which match targets by length
+	RuleWildcardLength
+	// RuleWildcardPrefix : the source ends with wildcards, so the rest must start the target
+	RuleWildcardPrefix
+	// RuleWildcardSuffix : the source starts with wildcards, so the rest must end the target
+	RuleWildcardSuffix
+	// RuleWildcardInfix : the source starts and ends with wildcards, so the rest must be in the target
+	RuleWildcardInfix
+)
+
+var rules = map[Rule]string{
+	RuleEqualValues:     "EQUAL_VALUES",
+	RuleTargetWildcards: "TARGET_WILDCARDS",
+	RuleANYSuperset:     "ANY_SUPERSET",
+	RuleANYSubset:       "ANY_SUBSET",
+	RuleNADisjoint:      "NA_DISJOINT",
+	RuleEmptySource:     "EMPTY_SOURCE",
+	RuleCaseInsensitive: "CASE_INSENSITIVE",
+	RuleStringMismatch:  "STRING_MISMATCH",
+	RuleWildcardLength:  "WILDCARD_LENGTH",
+	RuleWildcardPrefix:  "WILDCARD_PREFIX",
+	RuleWildcardSuffix:  "WILDCARD_SUFFIX",
+	RuleWildcardInfix:   "WILDCARD_INFIX",
+}
+
+// String returns string representation of the Rule
+func (r Rule) String() string {
+	if s, ok := rules[r]; ok {
+		return s
+	}
+	return "UNKNOWN"
+}
+
+// AttributeExplanation explains the relation of one attribute value pair.
+type AttributeExplanation struct {
+	Attribute string
+	// Source and Target are the values compared, a string or a LogicalValue
+	Source   interface{}
+	Target   interface{}
+	Relation Relation
+	Rule     Rule
+}
+
+// MatchExplanation explains the relation between a source and a target WFN.
+type MatchExplanation struct {
+	// Relation is the relation of the names as a whole, as Relate returns it
+	Relation Relation
+	// Attributes holds an explanation for each attribute, in the order of common.Attributes
+	Attributes []AttributeExplanation
+}
+
+// Explain compares two WFNs as Relate does, and tells why each attribute
+// has its relation.
+// @param source Source WFN
+// @param target Target WFN
+// @return MatchExplanation
+func Explain(source, target common.WellFormedName) MatchExplanation {
+	return Compile(source).Explain(target)
+}
+
+// Explain compares the source and a target WFN as Relate does, and tells
+// why each attribute has its relation.
+// @param target Target WFN
+// @return MatchExplanation
+func (m *Matcher) Explain(target common.WellFormedName) MatchExplanation {
+	var e MatchExplanation
+	var rel relator
+	for i, attr := range attributes {
+		t := target.Get(attr)
+		r, rule := m.values[i].explain(t)
+		rel.add(r)
+		e.Attributes = append(e.Attributes, AttributeExplanation{
+			Attribute: attr,
+			Source:    m.values[i].raw,
+			Target:    t,
+			Relation:  r,
+			Rule:      rule,
+		})
+	}
+	e.Relation = rel.relation()
+	return e
+}
+
+// Mismatches returns the attributes that keep the source from being a
+// superset of or equal to the target, i.e. those that make IsSuperset false.
+// @return explanations of the attributes whose relation is neither SUPERSET nor EQUAL
+func (e MatchExplanation) Mismatches() []AttributeExplanation {
+	var result []AttributeExplanation
+	for _, a := range e.Attributes {
+		if a.Relation != SUPERSET && a.Relation != EQUAL {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+// String renders the explanation as text: the relation of the names, then
+// an aligned line per attribute with the source and target values in the
+// WFN text form, their relation and the rule, e.g.
+//
+//	DISJOINT
+//	part     "a"          "a"          EQUAL     EQUAL_VALUES
+//	vendor   "microsoft"  "Microsoft"  SUPERSET  CASE_INSENSITIVE
+//	version  "8\.*"       "7\.0"       DISJOINT  WILDCARD_PREFIX
+//	...
+func (e MatchExplanation) String() string {
+	var b strings.Builder
+	b.WriteString(e.Relation.String())
+	b.WriteByte('\n')
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	for _, a := range e.Attributes {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", a.Attribute, valueText(a.Source), valueText(a.Target), a.Relation, a.Rule)
+	}
+	w.Flush()
+	return b.String()
+}
+
+// valueText returns a value in the WFN text form: logical values are bare
+// and strings are enclosed in double quotes.
+func valueText(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return `"` + s + `"`
+	}
+	return fmt.Sprint(v)
+}
+
+// jsonValue is the JSON form of an attribute value.
+type jsonValue struct {
+	// Kind is ANY, NA, LITERAL or PATTERN, as common.ValueKind names them
+	Kind  string `json:"kind"`
+	Value string `json:"value,omitempty"`
+}
+
+func newJSONValue(v interface{}) jsonValue {
+	av, err := common.NewAttributeValue(v)
+	if err != nil {
+		return jsonValue{Kind: "UNKNOWN", Value: fmt.Sprint(v)}
+	}
+	if av.IsLogical() {
+		return jsonValue{Kind: av.Kind().String()}
+	}
+	return jsonValue{Kind: av.Kind().String(), Value: av.String()}
+}
+
+// MarshalJSON encodes the explanation of an attribute, with the relation and
+// the rule by name and the values as objects holding their kind, e.g.
+// {"attribute":"vendor","source":{"kind":"ANY"},"target":{"kind":"LITERAL","value":"microsoft"},"relation":"SUPERSET","rule":"ANY_SUPERSET"}
+func (a AttributeExplanation) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Attribute string    `json:"attribute"`
+		Source    jsonValue `json:"source"`
+		Target    jsonValue `json:"target"`
+		Relation  string    `json:"relation"`
+		Rule      string    `json:"rule"`
+	}{a.Attribute, newJSONValue(a.Source), newJSONValue(a.Target), a.Relation.String(), a.Rule.String()})
+}
+
+// MarshalJSON encodes the explanation with the relation by name,
+// e.g. {"relation":"SUBSET","attributes":[...]}
+func (e MatchExplanation) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Relation   string                 `json:"relation"`
+		Attributes []AttributeExplanation `json:"attributes"`
+	}{e.Relation.String(), e.Attributes})
+}
@@ -0,0 +1,157 @@
+package matching
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/knqyf263/go-cpe/common"
+	"github.com/knqyf263/go-cpe/naming"
+)
+
+func TestExplain(t *testing.T) {
+	vectors := []struct {
+		source   string
+		target   string
+		relation Relation
+		// rules holds the expected rules of the attributes that are not EQUAL_VALUES
+		rules map[string]Rule
+	}{{
+		source:   `cpe:2.3:a:microsoft:internet_explorer:8.*:*:*:*:*:*:*:*`,
+		target:   `cpe:2.3:a:Microsoft:internet_explorer:7.0:-:*:*:*:*:*:*`,
+		relation: DISJOINT,
+		rules: map[string]Rule{
+			common.AttributeVendor:  RuleCaseInsensitive,
+			common.AttributeVersion: RuleWildcardPrefix,
+			common.AttributeUpdate:  RuleANYSuperset,
+		},
+	}, {
+		source:   `cpe:2.3:a:apache:*_server:-:*:*:*:*:*:*:*`,
+		target:   `cpe:2.3:a:apache:http_server:*:*:*:*:*:*:*:*`,
+		relation: UNDEFINED,
+		rules: map[string]Rule{
+			common.AttributeProduct: RuleWildcardSuffix,
+			common.AttributeVersion: RuleANYSubset,
+		},
+	}, {
+		source:   `cpe:2.3:o:*:*windows*:??:*:*:*:*:*:*:*`,
+		target:   `cpe:2.3:o:microsoft:windows_7:sp1:*:*:*:*:*:*:*`,
+		relation: DISJOINT,
+		rules: map[string]Rule{
+			common.AttributeVendor:  RuleANYSuperset,
+			common.AttributeProduct: RuleWildcardInfix,
+			common.AttributeVersion: RuleWildcardLength,
+		},
+	}, {
+		source:   `cpe:2.3:a:foo:bar:1.0:*:*:*:*:*:*:*`,
+		target:   `cpe:2.3:a:foo:baz:1.*:*:*:*:*:*:*:*`,
+		relation: DISJOINT,
+		rules: map[string]Rule{
+			common.AttributeProduct: RuleStringMismatch,
+			common.AttributeVersion: RuleTargetWildcards,
+		},
+	}, {
+		source:   `cpe:2.3:h:cisco:asa_5505:-:*:*:*:*:*:*:*`,
+		target:   `cpe:2.3:h:cisco:asa_5505:1.0:*:*:*:*:*:*:*`,
+		relation: DISJOINT,
+		rules: map[string]Rule{
+			common.AttributeVersion: RuleNADisjoint,
+		},
+	},
+	}
+
+	for i, v := range vectors {
+		source, err := naming.UnbindFS(v.source)
+		if err != nil {
+			t.Fatalf("test %d, UnbindFS: %v", i, err)
+		}
+		target, err := naming.UnbindFS(v.target)
+		if err != nil {
+			t.Fatalf("test %d, UnbindFS: %v", i, err)
+		}
+		e := Explain(source, target)
+		if e.Relation != v.relation || e.Relation != Relate(source, target) {
+			t.Errorf("test %d, Relation: got %v, want %v", i, e.Relation, v.relation)
+		}
+		relations := CompareWFNs(source, target)
+		if len(e.Attributes) != len(relations) {
+			t.Fatalf("test %d, got %d attributes, want %d", i, len(e.Attributes), len(relations))
+		}
+		for _, a := range e.Attributes {
+			rule, ok := v.rules[a.Attribute]
+			if !ok {
+				rule = RuleEqualValues
+			}
+			if a.Rule != rule {
+				t.Errorf("test %d, %s: got rule %v, want %v", i, a.Attribute, a.Rule, rule)
+			}
+			if a.Relation != relations[a.Attribute] {
+				t.Errorf("test %d, %s: got relation %v, want %v", i, a.Attribute, a.Relation, relations[a.Attribute])
+			}
+			if a.Source != source.Get(a.Attribute) || a.Target != target.Get(a.Attribute) {
+				t.Errorf("test %d, %s: got values %v, %v", i, a.Attribute, a.Source, a.Target)
+			}
+		}
+	}
+
+	if r := Explain(common.WellFormedName{"part": "a", "vendor": ""}, common.WellFormedName{"part": "a", "vendor": "foo"}); r.Attributes[1].Rule != RuleEmptySource {
+		t.Errorf("empty source: got rule %v, want %v", r.Attributes[1].Rule, RuleEmptySource)
+	}
+}
+
+func TestMatchExplanationString(t *testing.T) {
+	source := common.WellFormedName{"part": "a", "vendor": "microsoft", "product": "internet_explorer", "version": `8\.*`}
+	target := common.WellFormedName{"part": "a", "vendor": "Microsoft", "product": "internet_explorer", "version": `7\.0`,
+		"update": common.LogicalValue{Na: true}}
+	e := Explain(source, target)
+
+	expected := `DISJOINT
+part        "a"                  "a"                  EQUAL     EQUAL_VALUES
+vendor      "microsoft"          "Microsoft"          SUPERSET  CASE_INSENSITIVE
+product     "internet_explorer"  "internet_explorer"  EQUAL     EQUAL_VALUES
+version     "8\.*"               "7\.0"               DISJOINT  WILDCARD_PREFIX
+update      ANY                  NA                   SUPERSET  ANY_SUPERSET
+edition     ANY                  ANY                  EQUAL     EQUAL_VALUES
+language    ANY                  ANY                  EQUAL     EQUAL_VALUES
+sw_edition  ANY                  ANY                  EQUAL     EQUAL_VALUES
+target_sw   ANY                  ANY                  EQUAL     EQUAL_VALUES
+target_hw   ANY                  ANY                  EQUAL     EQUAL_VALUES
+other       ANY                  ANY                  EQUAL     EQUAL_VALUES
+`
+	if actual := e.String(); actual != expected {
+		t.Errorf("String: got\n%s\nwant\n%s", actual, expected)
+	}
+
+	mismatches := e.Mismatches()
+	if len(mismatches) != 1 || mismatches[0].Attribute != common.AttributeVersion {
+		t.Errorf("Mismatches: got %v", mismatches)
+	}
+}
+
+func TestMatchExplanationJSON(t *testing.T) {
+	e := MatchExplanation{
+		Relation: SUBSET,
+		Attributes: []AttributeExplanation{{
+			Attribute: common.AttributeVendor,
+			Source:    "microsoft",
+			Target:    common.LogicalValue{Any: true},
+			Relation:  SUBSET,
+			Rule:      RuleANYSubset,
+		}, {
+			Attribute: common.AttributeVersion,
+			Source:    `8\.*`,
+			Target:    `8\.0`,
+			Relation:  SUPERSET,
+			Rule:      RuleWildcardPrefix,
+		}},
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"relation":"SUBSET","attributes":[` +
+		`{"attribute":"vendor","source":{"kind":"LITERAL","value":"microsoft"},"target":{"kind":"ANY"},"relation":"SUBSET","rule":"ANY_SUBSET"},` +
+		`{"attribute":"version","source":{"kind":"PATTERN","value":"8\\.*"},"target":{"kind":"LITERAL","value":"8\\.0"},"relation":"SUPERSET","rule":"WILDCARD_PREFIX"}]}`
+	if string(b) != expected {
+		t.Errorf("MarshalJSON: got %s, want %s", b, expected)
+	}
+}
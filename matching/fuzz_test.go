@@ -0,0 +1,111 @@
+//go:build go1.18
+// +build go1.18
+
+package matching
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/knqyf263/go-cpe/common"
+	"github.com/knqyf263/go-cpe/naming"
+	"github.com/pkg/errors"
+)
+
+func FuzzCompareValues(f *testing.F) {
+	f.Add("foo*", "foobar")
+	f.Add("?", `\?`)
+	f.Add("*", "")
+	f.Add(`\`, `\*`)
+	f.Fuzz(func(t *testing.T, source, target string) {
+		// Values that fail validation must not panic either.
+		CompareValues(source, target)
+		CompareValues(target, source)
+	})
+}
+
+func FuzzCompareWFNs(f *testing.F) {
+	f.Add("cpe:2.3:a:microsoft:internet_explorer:8.*:sp?:*:*:*:*:*:*", "cpe:2.3:a:microsoft:internet_explorer:8.0.6001:sp2:*:*:*:*:*:*")
+	f.Add("cpe:2.3:a:*:???:-:*:*:*:*:*:*:*", `cpe:2.3:a:\*:\?\?:1:*:*:*:*:*:*:*`)
+	f.Fuzz(func(t *testing.T, s1, s2 string) {
+		source, err := naming.UnbindFS(s1)
+		if err != nil {
+			return
+		}
+		target, err := naming.UnbindFS(s2)
+		if err != nil {
+			return
+		}
+		relations := CompareWFNs(source, target)
+		if actual := Compile(source).Compare(target); !reflect.DeepEqual(actual, relations) {
+			t.Fatalf("Compile(%q).Compare(%q) = %v, want %v", s1, s2, actual, relations)
+		}
+		e := Explain(source, target)
+		if e.Relation != Relate(source, target) {
+			t.Fatalf("Explain(%q, %q).Relation = %v, want %v", s1, s2, e.Relation, Relate(source, target))
+		}
+		for _, a := range e.Attributes {
+			if a.Relation != relations[a.Attribute] {
+				t.Fatalf("Explain(%q, %q) %s = %v, want %v", s1, s2, a.Attribute, a.Relation, relations[a.Attribute])
+			}
+		}
+		if IsEqual(source, target) != IsEqual(target, source) {
+			t.Fatalf("IsEqual is not symmetric for %q and %q", s1, s2)
+		}
+		if IsSuperset(source, target) && IsDisjoint(source, target) {
+			t.Fatalf("%q is both a superset and disjoint of %q", s1, s2)
+		}
+	})
+}
+
+func FuzzIntersect(f *testing.F) {
+	f.Add("cpe:2.3:a:*:foo*:*:*:*:*:*:*:*:*", "cpe:2.3:a:acme:?oob*:*:*:*:*:*:*:*:*", "cpe:2.3:a:acme:foobar:1:beta:x:en:y:z:w:-")
+	f.Add("cpe:2.3:a:acme:???:-:*:*:*:*:*:*:*", "cpe:2.3:a:acme:*o:*:*:*:*:*:*:*:*", "cpe:2.3:a:acme:foo:-:a:b:c:d:e:f:g")
+	f.Add("cpe:2.3:a:acme:foo:1.0:*:*:*:*:*:*:*", "cpe:2.3:a:acme:foo:1.*:*:*:*:*:*:*:*", "cpe:2.3:a:acme:foo:1.01:-:-:-:-:-:-:-")
+	f.Fuzz(func(t *testing.T, s1, s2, s3 string) {
+		a, err := naming.UnbindFS(s1)
+		if err != nil {
+			return
+		}
+		b, err := naming.UnbindFS(s2)
+		if err != nil {
+			return
+		}
+		name, err := naming.UnbindFS(s3)
+		if err != nil || !isLiteral(name) {
+			return
+		}
+		c, err := Intersect(a, b)
+		both := IsSuperset(a, name) && IsSuperset(b, name)
+		switch errors.Cause(err) {
+		case nil:
+			if IsSuperset(c, name) != both {
+				t.Fatalf("Intersect(%q, %q) = %v, which differs on %q", s1, s2, c, s3)
+			}
+			if !Covers([]common.WellFormedName{a}, c) {
+				t.Fatalf("%q does not cover Intersect(%q, %q) = %v", s1, s1, s2, c)
+			}
+		case ErrEmptyIntersection:
+			if both {
+				t.Fatalf("Intersect(%q, %q) is empty, but both hold %q", s1, s2, s3)
+			}
+		}
+	})
+}
+
+// isLiteral tests whether every value of a name is NA or a string without wildcards
+func isLiteral(wfn common.WellFormedName) bool {
+	for _, attr := range attributes {
+		switch v := wfn.Get(attr).(type) {
+		case common.LogicalValue:
+			if v.IsANY() {
+				return false
+			}
+		case string:
+			if common.ContainsWildcards(v) {
+				return false
+			}
+		}
+	}
+	return true
+}
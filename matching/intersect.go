@@ -0,0 +1,479 @@
+package matching
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/knqyf263/go-cpe/common"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrEmptyIntersection is returned when no name is in the sets of both WFNs
+	ErrEmptyIntersection = errors.New("Empty intersection")
+	// ErrNotRepresentable is returned when an intersection is not the set of any single WFN
+	ErrNotRepresentable = errors.New("Intersection is not representable")
+)
+
+// Intersect returns the WFN whose set is the intersection of the sets of two
+// WFNs.  The set of a WFN holds the names without wildcards or ANY that it is
+// a superset of, so that IsSuperset(result, name) holds exactly when both
+// IsSuperset(a, name) and IsSuperset(b, name) hold.  A value whose set
+// contains the other is dropped, and the other is kept as is; otherwise the
+// values are merged, e.g. "foo*" and "?oob*" give "foob*".
+// @param a WFN
+// @param b WFN
+// @return intersection, or ErrEmptyIntersection if the names are disjoint, or
+// ErrNotRepresentable if no WFN has the intersection as its set, e.g. for
+// "foo*" and "*bar", or if a value is not a valid string
+func Intersect(a, b common.WellFormedName) (common.WellFormedName, error) {
+	result := common.WellFormedName{}
+	var notRepresentable error
+	for _, attr := range attributes {
+		v, err := IntersectValues(a.Get(attr), b.Get(attr))
+		switch errors.Cause(err) {
+		case nil:
+			result[attr] = v
+		case ErrEmptyIntersection:
+			// an empty attribute empties the names, whatever the other attributes are
+			return nil, errors.Wrapf(err, "Attribute %s", attr)
+		default:
+			if notRepresentable == nil {
+				notRepresentable = errors.Wrapf(err, "Attribute %s", attr)
+			}
+		}
+	}
+	if notRepresentable != nil {
+		return nil, notRepresentable
+	}
+	return result, nil
+}
+
+// IntersectValues returns the attribute value whose set is the intersection
+// of the sets of two values, as Intersect does for each attribute.
+// @param a attribute value, a string or a LogicalValue
+// @param b attribute value, a string or a LogicalValue
+// @return intersection, or ErrEmptyIntersection or ErrNotRepresentable
+func IntersectValues(a, b interface{}) (interface{}, error) {
+	x, errA := newValueSet(a)
+	y, errB := newValueSet(b)
+	switch {
+	case errA == nil && x.isANY():
+		return b, nil
+	case errB == nil && y.isANY():
+		return a, nil
+	case errA != nil:
+		return nil, errors.Wrap(ErrNotRepresentable, errA.Error())
+	case errB != nil:
+		return nil, errors.Wrap(ErrNotRepresentable, errB.Error())
+	case y.contains(x):
+		return a, nil
+	case x.contains(y):
+		return b, nil
+	}
+	s, err := x.intersect(y)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s and %s", valueText(a), valueText(b))
+	}
+	return s.value(), nil
+}
+
+// unbounded is the bound of the characters a * wildcard matches
+const unbounded = math.MaxInt32
+
+// valueSet is the set of an attribute value: NA and the strings it matches.
+type valueSet struct {
+	na      bool
+	strings bool
+	// shape holds the strings, if strings is true
+	shape shape
+}
+
+// newValueSet finds the set of an attribute value.
+// @param v attribute value
+// @return valueSet, or an error if v is a string that is not a valid WFN value
+func newValueSet(v interface{}) (valueSet, error) {
+	av, err := common.NewAttributeValue(v)
+	if err != nil {
+		return valueSet{}, err
+	}
+	switch {
+	case av.IsANY():
+		return valueSet{na: true, strings: true, shape: shape{left: unbounded}}, nil
+	case av.IsNA():
+		return valueSet{na: true}, nil
+	}
+	sh, ok := parseShape(av.Value())
+	if !ok {
+		return valueSet{}, errors.Errorf("Unsupported value %q", av.Value())
+	}
+	return valueSet{strings: true, shape: sh}, nil
+}
+
+func (s valueSet) isANY() bool {
+	return s.na && s.strings && len(s.shape.body) == 0 && s.shape.left == unbounded && s.shape.min == 0
+}
+
+// contains tests whether the set holds every name of another set
+func (s valueSet) contains(t valueSet) bool {
+	if t.na && !s.na {
+		return false
+	}
+	return !t.strings || s.strings && s.shape.contains(t.shape)
+}
+
+func (s valueSet) intersect(t valueSet) (valueSet, error) {
+	r := valueSet{na: s.na && t.na}
+	if s.strings && t.strings {
+		sh, err := s.shape.intersect(t.shape)
+		switch {
+		case err == nil:
+			r.strings, r.shape = true, sh
+		case err != ErrEmptyIntersection:
+			return r, err
+		}
+	}
+	switch {
+	case !r.na && !r.strings:
+		return r, ErrEmptyIntersection
+	case r.na && r.strings && !r.isANY():
+		// NA and strings are only held together by ANY
+		return r, ErrNotRepresentable
+	}
+	return r, nil
+}
+
+// value returns the attribute value of the set
+func (s valueSet) value() interface{} {
+	switch {
+	case s.strings && s.na:
+		return common.LogicalValue{Any: true}
+	case s.na:
+		return common.LogicalValue{Na: true}
+	}
+	return s.shape.String()
+}
+
+// unit is a character of a string value.
+type unit struct {
+	// text is the character as written in the value, with its backslash if quoted
+	text string
+	// key is text in lowercase, as matching is case insensitive
+	key string
+}
+
+// shape is the set of strings x+body+y where x has at most left characters,
+// y at most right characters, and the string at least min characters.
+// A valid string value is one of:
+//
+//	body          left = right = 0
+//	??body*       left = 2, right = unbounded
+//	???           empty body, left = 3
+//	??*           empty body, left = unbounded, min = 2
+type shape struct {
+	left, right int
+	body        []unit
+	// min is only set for shapes with an empty body
+	min int
+}
+
+// parseShape finds the shape of a string value: the wildcards at its
+// beginning and end, and its body.
+// @param s string value
+// @return shape, or false if s holds wildcards the shapes cannot express
+func parseShape(s string) (shape, bool) {
+	var units []unit
+	// wild holds the wildcard before each unit, and the trailing ones last
+	wild := []string{""}
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == '*' || r == '?':
+			wild[len(wild)-1] += string(r)
+			i += size
+			continue
+		case r == '\\':
+			if i+size == len(s) {
+				return shape{}, false
+			}
+			_, n := utf8.DecodeRuneInString(s[i+size:])
+			size += n
+		}
+		text := s[i : i+size]
+		units = append(units, unit{text: text, key: strings.ToLower(text)})
+		wild = append(wild, "")
+		i += size
+	}
+	if len(units) == 0 {
+		w := wild[0]
+		switch {
+		case !strings.Contains(w, "*"):
+			return shape{left: len(w)}, true
+		case strings.Count(w, "*") == 1 && (w[0] == '*' || w[len(w)-1] == '*'):
+			return shape{left: unbounded, min: len(w) - 1}, true
+		}
+		return shape{}, false
+	}
+	for _, w := range wild[1 : len(wild)-1] {
+		if w != "" {
+			return shape{}, false
+		}
+	}
+	left, ok := parseBound(wild[0])
+	if !ok {
+		return shape{}, false
+	}
+	right, ok := parseBound(wild[len(wild)-1])
+	if !ok {
+		return shape{}, false
+	}
+	return shape{left: left, body: units, right: right}, true
+}
+
+// parseBound returns the most characters matched by the wildcards at one end of a body
+func parseBound(w string) (int, bool) {
+	switch {
+	case w == "*":
+		return unbounded, true
+	case strings.Trim(w, "?") == "":
+		return len(w), true
+	}
+	return 0, false
+}
+
+// String returns the string value of the shape
+func (sh shape) String() string {
+	if len(sh.body) == 0 {
+		if sh.left == unbounded {
+			return strings.Repeat("?", sh.min) + "*"
+		}
+		return strings.Repeat("?", sh.left)
+	}
+	var b strings.Builder
+	b.WriteString(wildcards(sh.left))
+	for _, u := range sh.body {
+		b.WriteString(u.text)
+	}
+	b.WriteString(wildcards(sh.right))
+	return b.String()
+}
+
+func wildcards(n int) string {
+	if n == unbounded {
+		return "*"
+	}
+	return strings.Repeat("?", n)
+}
+
+// maxLen returns the most characters of the strings of the shape, or unbounded
+func (sh shape) maxLen() int {
+	return add(len(sh.body), add(sh.left, sh.right))
+}
+
+// minLen returns the least characters of the strings of the shape
+func (sh shape) minLen() int {
+	return maxInt(len(sh.body), sh.min)
+}
+
+// contains tests whether the shape holds every string of another shape.
+// The characters around the body of t may be any, so its strings are only
+// held if the body of sh is in the body of t, close enough to the ends.
+func (sh shape) contains(t shape) bool {
+	if t.minLen() < sh.min {
+		return false
+	}
+	if len(sh.body) == 0 {
+		return t.maxLen() <= sh.maxLen()
+	}
+	for q := indexUnits(t.body, sh.body, 0); q != -1; q = indexUnits(t.body, sh.body, q+1) {
+		if add(t.left, q) <= sh.left && add(t.right, len(t.body)-q-len(sh.body)) <= sh.right {
+			return true
+		}
+	}
+	return false
+}
+
+// intersect returns the shape holding the strings of both shapes.
+func (sh shape) intersect(t shape) (shape, error) {
+	switch {
+	case t.contains(sh):
+		return sh, nil
+	case sh.contains(t):
+		return t, nil
+	}
+	// the least length is applied last: only ??* shapes have one
+	least := maxInt(sh.min, t.min)
+	sh.min, t.min = 0, 0
+	var r shape
+	var err error
+	switch {
+	case len(sh.body) == 0:
+		r, err = t.within(sh.left)
+	case len(t.body) == 0:
+		r, err = sh.within(t.left)
+	default:
+		r, err = align(sh, t)
+	}
+	if err != nil {
+		return r, err
+	}
+	return r.atLeast(least)
+}
+
+// within returns the strings of the shape that have at most n characters
+func (sh shape) within(n int) (shape, error) {
+	if n == unbounded {
+		return sh, nil
+	}
+	extra := n - len(sh.body)
+	if extra < 0 {
+		return shape{}, ErrEmptyIntersection
+	}
+	r := shape{left: minInt(sh.left, extra), body: sh.body, right: minInt(sh.right, extra)}
+	if r.left+r.right > extra {
+		// e.g. ?foo? within 4 characters, which holds "xfoo" and "foox" but not "xfoox"
+		return shape{}, ErrNotRepresentable
+	}
+	if len(r.body) == 0 {
+		r.left, r.right = r.left+r.right, 0
+	}
+	return r, nil
+}
+
+// atLeast returns the strings of the shape that have at least n characters
+func (sh shape) atLeast(n int) (shape, error) {
+	switch {
+	case n <= sh.minLen():
+		return sh, nil
+	case sh.maxLen() < n:
+		return shape{}, ErrEmptyIntersection
+	case len(sh.body) == 0 && sh.left == unbounded:
+		sh.min = n
+		return sh, nil
+	}
+	return shape{}, ErrNotRepresentable
+}
+
+// gap is the set of strings x+first+g+second+y where g has between one and
+// gap characters, used as a superset of the strings matched by two shapes
+// whose bodies are apart.
+type gap struct {
+	left, gap, right int
+	first, second    []unit
+}
+
+// inside tests whether a shape with a body holds every string of the gap
+func (g gap) inside(sh shape) bool {
+	n := len(sh.body)
+	for q := indexUnits(g.first, sh.body, 0); q != -1; q = indexUnits(g.first, sh.body, q+1) {
+		if add(g.left, q) <= sh.left && add(len(g.first)-q-n, add(g.gap, add(len(g.second), g.right))) <= sh.right {
+			return true
+		}
+	}
+	for q := indexUnits(g.second, sh.body, 0); q != -1; q = indexUnits(g.second, sh.body, q+1) {
+		if add(g.left, add(len(g.first), add(g.gap, q))) <= sh.left && add(len(g.second)-q-n, g.right) <= sh.right {
+			return true
+		}
+	}
+	return false
+}
+
+// align intersects two shapes with bodies.  A string of both holds the two
+// bodies, which either overlap, giving a merged body for each offset at which
+// they agree, or are apart.  The intersection is representable if one of the
+// merged shapes holds all the others and every string with the bodies apart.
+func align(a, b shape) (shape, error) {
+	n1, n2 := len(a.body), len(b.body)
+	var merged []shape
+	for d := -n2; d <= n1; d++ {
+		// the body of b starts d characters after the body of a
+		off1, off2 := maxInt(0, -d), maxInt(0, d)
+		body := make([]unit, maxInt(off1+n1, off2+n2))
+		copy(body[off1:], a.body)
+		agree := true
+		for i, u := range b.body {
+			if p := off2 + i; p >= off1 && p < off1+n1 {
+				agree = agree && body[p].key == u.key
+			} else {
+				body[p] = u
+			}
+		}
+		left := minInt(sub(a.left, off1), sub(b.left, off2))
+		right := minInt(sub(a.right, len(body)-off1-n1), sub(b.right, len(body)-off2-n2))
+		if agree && left >= 0 && right >= 0 {
+			merged = append(merged, shape{left: left, body: body, right: right})
+		}
+	}
+	var gaps []gap
+	if g := minInt(sub(b.left, n1), sub(a.right, n2)); g >= 1 {
+		gaps = append(gaps, gap{left: minInt(a.left, sub(b.left, n1+1)), gap: g, right: minInt(b.right, sub(a.right, n2+1)), first: a.body, second: b.body})
+	}
+	if g := minInt(sub(a.left, n2), sub(b.right, n1)); g >= 1 {
+		gaps = append(gaps, gap{left: minInt(b.left, sub(a.left, n2+1)), gap: g, right: minInt(a.right, sub(b.right, n1+1)), first: b.body, second: a.body})
+	}
+	if len(merged) == 0 && len(gaps) == 0 {
+		return shape{}, ErrEmptyIntersection
+	}
+
+	for _, c := range merged {
+		holds := true
+		for _, m := range merged {
+			holds = holds && c.contains(m)
+		}
+		for _, g := range gaps {
+			holds = holds && g.inside(c)
+		}
+		if holds {
+			return c, nil
+		}
+	}
+	return shape{}, ErrNotRepresentable
+}
+
+// indexUnits returns the index of the first occurrence of sub in s at or after from, or -1
+func indexUnits(s, sub []unit, from int) int {
+	for i := from; i+len(sub) <= len(s); i++ {
+		match := true
+		for j := range sub {
+			if s[i+j].key != sub[j].key {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
+
+// add adds two bounds, either of which may be unbounded
+func add(a, b int) int {
+	if a == unbounded || b == unbounded {
+		return unbounded
+	}
+	return a + b
+}
+
+// sub subtracts n from a bound, which stays unbounded if it is
+func sub(a, n int) int {
+	if a == unbounded {
+		return a
+	}
+	return a - n
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,223 @@
+package matching
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/knqyf263/go-cpe/common"
+	"github.com/knqyf263/go-cpe/naming"
+	"github.com/pkg/errors"
+)
+
+func TestIntersect(t *testing.T) {
+	vectors := []struct {
+		a        string
+		b        string
+		expected string
+		wantErr  error
+	}{{
+		a:        `cpe:2.3:a:*:foo*:*:*:*:*:*:*:*:*`,
+		b:        `cpe:2.3:a:acme:*:*:*:*:*:*:*:*:*`,
+		expected: `cpe:2.3:a:acme:foo*:*:*:*:*:*:*:*:*`,
+	}, {
+		a:        `cpe:2.3:a:microsoft:internet_explorer:8.*:*:*:*:*:*:*:*`,
+		b:        `cpe:2.3:a:Microsoft:*:*:-:*:*:*:*:*:*`,
+		expected: `cpe:2.3:a:microsoft:internet_explorer:8.*:-:*:*:*:*:*:*`,
+	}, {
+		a:        `cpe:2.3:a:acme:foo*:*:*:*:*:*:*:*:*`,
+		b:        `cpe:2.3:a:acme:?oob*:*:*:*:*:*:*:*:*`,
+		expected: `cpe:2.3:a:acme:foob*:*:*:*:*:*:*:*:*`,
+	}, {
+		a:       `cpe:2.3:a:acme:foo:*:*:*:*:*:*:*:*`,
+		b:       `cpe:2.3:o:acme:foo:*:*:*:*:*:*:*:*`,
+		wantErr: ErrEmptyIntersection,
+	}, {
+		a:       `cpe:2.3:a:acme:foo*:*:*:*:*:*:*:*:*`,
+		b:       `cpe:2.3:a:acme:*bar:*:*:*:*:*:*:*:*`,
+		wantErr: ErrNotRepresentable,
+	}, {
+		// an empty attribute empties the names, even if another is not representable
+		a:       `cpe:2.3:a:acme:foo*:1.0:*:*:*:*:*:*:*`,
+		b:       `cpe:2.3:a:acme:*bar:-:*:*:*:*:*:*:*`,
+		wantErr: ErrEmptyIntersection,
+	},
+	}
+
+	for i, v := range vectors {
+		a, err := naming.UnbindFS(v.a)
+		if err != nil {
+			t.Fatalf("test %d, UnbindFS: %v", i, err)
+		}
+		b, err := naming.UnbindFS(v.b)
+		if err != nil {
+			t.Fatalf("test %d, UnbindFS: %v", i, err)
+		}
+		actual, err := Intersect(a, b)
+		if errors.Cause(err) != v.wantErr {
+			t.Errorf("test %d, Error: got %v, want %v", i, err, v.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		expected, err := naming.UnbindFS(v.expected)
+		if err != nil {
+			t.Fatalf("test %d, UnbindFS: %v", i, err)
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("test %d, Intersect: got %v, want %v", i, actual, expected)
+		}
+	}
+}
+
+func TestIntersectValues(t *testing.T) {
+	vectors := []struct {
+		a        interface{}
+		b        interface{}
+		expected interface{}
+		wantErr  error
+	}{
+		{a: common.LogicalValue{Any: true}, b: "foo", expected: "foo"},
+		{a: common.LogicalValue{Na: true}, b: common.LogicalValue{Any: true}, expected: common.LogicalValue{Na: true}},
+		{a: common.LogicalValue{Na: true}, b: "foo", wantErr: ErrEmptyIntersection},
+		{a: "Foo", b: "foo", expected: "Foo"},
+		{a: "foo", b: "bar", wantErr: ErrEmptyIntersection},
+		{a: "foo*", b: "foobar", expected: "foobar"},
+		{a: "foo*", b: "*bar", wantErr: ErrNotRepresentable},
+		{a: "*foo*", b: "?foo", expected: "?foo"},
+		{a: "???", b: "ab*", expected: "ab?"},
+		{a: "???", b: "*ab", expected: "?ab"},
+		{a: "???", b: "*a*", wantErr: ErrNotRepresentable},
+		{a: "?ab", b: "ab?", expected: "ab"},
+		{a: "a?", b: "?a", wantErr: ErrNotRepresentable},
+		{a: "??*", b: "*foo", expected: "*foo"},
+		{a: "??*", b: "???", wantErr: ErrNotRepresentable},
+		{a: "??*", b: "*?", expected: "??*"},
+		{a: "?????*", b: "foo?", wantErr: ErrEmptyIntersection},
+		{a: `8\.*`, b: `*\.0`, wantErr: ErrNotRepresentable},
+		{a: `8\.*`, b: `?\.0*`, expected: `8\.0*`},
+		{a: `8\.*`, b: `??\.0*`, wantErr: ErrNotRepresentable},
+		{a: "foo*", b: "bar*", wantErr: ErrEmptyIntersection},
+		{a: "foo*bar", b: "foo", wantErr: ErrNotRepresentable},
+		{a: `1\.0`, b: `1\.01`, wantErr: ErrEmptyIntersection},
+		{a: `1\.0`, b: `1\.0x`, wantErr: ErrEmptyIntersection},
+		{a: `1\.0?`, b: `?\.0*`, expected: `1\.0?`},
+		{a: `1\.*`, b: `*\.0`, wantErr: ErrNotRepresentable},
+	}
+
+	for i, v := range vectors {
+		actual, err := IntersectValues(v.a, v.b)
+		if errors.Cause(err) != v.wantErr {
+			t.Errorf("test %d, IntersectValues(%v, %v): got error %v, want %v", i, v.a, v.b, err, v.wantErr)
+			continue
+		}
+		if actual != v.expected {
+			t.Errorf("test %d, IntersectValues(%v, %v): got %v, want %v", i, v.a, v.b, actual, v.expected)
+		}
+	}
+}
+
+// testValues returns values built from short bodies over "a" and "b", with
+// every kind of wildcards, and NA and ANY
+func testValues() []interface{} {
+	bodies := []string{"a", "b", "aa", "ab", "ba", "bb"}
+	var values []interface{}
+	for _, left := range []string{"", "?", "??", "*"} {
+		for _, body := range bodies {
+			for _, right := range []string{"", "?", "??", "*"} {
+				values = append(values, left+body+right)
+			}
+		}
+	}
+	for _, v := range []string{"", "aab", "?", "??", "???", "?*", "??*", "*?"} {
+		values = append(values, v)
+	}
+	return append(values, common.LogicalValue{Any: true}, common.LogicalValue{Na: true})
+}
+
+// testNames returns NA and every string over "a", "b" and "z" of at most
+// six characters, which is longer than the bounds of testValues
+func testNames() []interface{} {
+	names := []interface{}{common.LogicalValue{Na: true}, ""}
+	for last := 1; len(names[last:]) > 0 && len(names[len(names)-1].(string)) < 6; {
+		from := last
+		last = len(names)
+		for _, n := range names[from:last] {
+			for _, c := range []string{"a", "b", "z"} {
+				names = append(names, n.(string)+c)
+			}
+		}
+	}
+	return names
+}
+
+// memberships returns whether each name is in the set of the value, as IsSuperset defines it
+func memberships(v interface{}, names []interface{}) []bool {
+	result := make([]bool, len(names))
+	m := compileValue(v)
+	for i, n := range names {
+		r := m.compare(n)
+		result[i] = r == SUPERSET || r == EQUAL
+	}
+	return result
+}
+
+func TestIntersectValuesBruteForce(t *testing.T) {
+	checkIntersections(t, testValues(), testNames())
+}
+
+func TestIntersectValuesQuoted(t *testing.T) {
+	values := []interface{}{`1\.0`, `1\.0?`, `1\.0*`, `?\.0`, `*\.0`, `1\.*`, `\.*`, `*\.`, `?\.?`, "1*", "??", "?*"}
+	// every name of at most four units
+	names := []interface{}{""}
+	level := []string{""}
+	for n := 0; n < 4; n++ {
+		var next []string
+		for _, s := range level {
+			for _, u := range []string{"1", "0", "z", `\.`} {
+				next = append(next, s+u)
+				names = append(names, s+u)
+			}
+		}
+		level = next
+	}
+	checkIntersections(t, values, names)
+}
+
+// checkIntersections checks the intersection of every pair of values against
+// the names the values are supersets of
+func checkIntersections(t *testing.T, values, names []interface{}) {
+	members := make([][]bool, len(values))
+	for i, v := range values {
+		members[i] = memberships(v, names)
+	}
+
+	counts := map[error]int{}
+	for i, a := range values {
+		for j, b := range values {
+			actual, err := IntersectValues(a, b)
+			counts[errors.Cause(err)]++
+			switch errors.Cause(err) {
+			case nil:
+				in := memberships(actual, names)
+				for k := range names {
+					if in[k] != (members[i][k] && members[j][k]) {
+						t.Fatalf("IntersectValues(%v, %v) = %v, which differs on %q", a, b, actual, names[k])
+					}
+				}
+			case ErrEmptyIntersection:
+				for k := range names {
+					if members[i][k] && members[j][k] {
+						t.Fatalf("IntersectValues(%v, %v) is empty, but both hold %q", a, b, names[k])
+					}
+				}
+			case ErrNotRepresentable:
+			default:
+				t.Fatalf("IntersectValues(%v, %v): %v", a, b, err)
+			}
+		}
+	}
+	if counts[nil] == 0 || counts[ErrEmptyIntersection] == 0 || counts[ErrNotRepresentable] == 0 {
+		t.Errorf("IntersectValues results: %v", counts)
+	}
+}
@@ -0,0 +1,224 @@
+package matching
+
+import (
+	"strings"
+
+	"github.com/knqyf263/go-cpe/common"
+)
+
+// attributes holds the WFN attributes in the order Matcher stores them
+var attributes = common.Attributes()
+
+// value is a source attribute value pre-processed by compileValue.
+type value struct {
+	// raw is the source value as given, which decides equality
+	raw interface{}
+	lv  common.LogicalValue
+	// pattern is compiled from the lowercase source string, or from ""
+	// if the source is not a string
+	pattern pattern
+}
+
+// compileValue lowercases a source attribute value and finds its wildcards.
+// @param source Source attribute value.
+// @return value to compare targets with
+func compileValue(source interface{}) value {
+	v := value{raw: source}
+	switch t := source.(type) {
+	case common.LogicalValue:
+		v.lv = t
+	case string:
+		// matching is case insensitive, convert strings to lowercase.
+		v.pattern = compilePattern(strings.ToLower(t))
+	}
+	return v
+}
+
+// compare compares the source value to a target attribute value.
+// @param target Target attribute value.
+// @return The relation between the two attribute values.
+func (v *value) compare(target interface{}) Relation {
+	r, _ := v.explain(target)
+	return r
+}
+
+// explain compares the source value to a target attribute value.
+// @param target Target attribute value.
+// @return The relation between the two attribute values, and the rule that decided it.
+func (v *value) explain(target interface{}) (Relation, Rule) {
+	t, ok := target.(string)
+	if ok {
+		// matching is case insensitive, convert strings to lowercase.
+		t = strings.ToLower(t)
+		// Unquoted wildcard characters yield an undefined result.
+		if common.ContainsWildcards(t) {
+			return UNDEFINED, RuleTargetWildcards
+		}
+	}
+
+	// If source and target values are equal, then result is equal.
+	if v.raw == target {
+		return EQUAL, RuleEqualValues
+	}
+
+	// Check to see if source or target are Logical Values.
+	lvTarget, _ := target.(common.LogicalValue)
+	// If source value is ANY, result is a superset.
+	if v.lv.IsANY() {
+		return SUPERSET, RuleANYSuperset
+	}
+	// If target value is ANY, result is a subset.
+	if lvTarget.IsANY() {
+		return SUBSET, RuleANYSubset
+	}
+	// If source or target is NA, result is disjoint.
+	if v.lv.IsNA() || lvTarget.IsNA() {
+		return DISJOINT, RuleNADisjoint
+	}
+	// only Strings will get to this point, not LogicalValues
+	r := v.pattern.match(t)
+	return r, v.pattern.rule(r)
+}
+
+// Matcher compares target WFNs to a source WFN that was pre-processed once by
+// Compile, which is faster than CompareWFNs when one source is compared to
+// many targets.  A Matcher is safe for concurrent use.
+type Matcher struct {
+	values [11]value
+}
+
+// Compile pre-processes a source WFN for repeated comparisons.
+// @param source Source WFN
+// @return Matcher giving the same relations as CompareWFNs(source, target)
+func Compile(source common.WellFormedName) *Matcher {
+	m := &Matcher{}
+	for i, attr := range attributes {
+		m.values[i] = compileValue(source.Get(attr))
+	}
+	return m
+}
+
+// Compare compares each attribute value pair in the source and a target WFN,
+// as CompareWFNs does.
+// @param target Target WFN
+// @return A map of attribute string to attribute value Relation
+func (m *Matcher) Compare(target common.WellFormedName) map[string]Relation {
+	result := make(map[string]Relation, len(attributes))
+	for i, attr := range attributes {
+		result[attr] = m.values[i].compare(target.Get(attr))
+	}
+	return result
+}
+
+// CompareValue compares the source value of one attribute to a target value,
+// as CompareValues does.
+// @param attribute String representing the attribute
+// @param target Target attribute value
+// @return The relation between the two attribute values, or UNDEFINED if the
+// attribute is not valid
+func (m *Matcher) CompareValue(attribute string, target interface{}) Relation {
+	for i, attr := range attributes {
+		if attr == attribute {
+			return m.values[i].compare(target)
+		}
+	}
+	return UNDEFINED
+}
+
+// IsDisjoint tests the source and a target WFN for disjointness, as IsDisjoint does.
+// @param target Target WFN
+// @return true if the names are disjoint, false otherwise
+func (m *Matcher) IsDisjoint(target common.WellFormedName) bool {
+	for i, attr := range attributes {
+		if m.values[i].compare(target.Get(attr)) == DISJOINT {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEqual tests the source and a target WFN for equality, as IsEqual does.
+// @param target Target WFN
+// @return true if the names are equal, false otherwise
+func (m *Matcher) IsEqual(target common.WellFormedName) bool {
+	for i, attr := range attributes {
+		if m.values[i].compare(target.Get(attr)) != EQUAL {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSubset tests if the source is a subset of a target WFN, as IsSubset does.
+// @param target Target WFN
+// @return true if the source is a subset of the target, false otherwise
+func (m *Matcher) IsSubset(target common.WellFormedName) bool {
+	for i, attr := range attributes {
+		if r := m.values[i].compare(target.Get(attr)); r != SUBSET && r != EQUAL {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset tests if the source is a superset of a target WFN, as IsSuperset does.
+// @param target Target WFN
+// @return true if the source is a superset of the target, false otherwise
+func (m *Matcher) IsSuperset(target common.WellFormedName) bool {
+	for i, attr := range attributes {
+		if r := m.values[i].compare(target.Get(attr)); r != SUPERSET && r != EQUAL {
+			return false
+		}
+	}
+	return true
+}
+
+// Relate compares the source and a target WFN as a whole.
+// @param target Target WFN
+// @return DISJOINT if IsDisjoint holds, else EQUAL if IsEqual holds, else
+// SUPERSET if IsSuperset holds, else SUBSET if IsSubset holds, else UNDEFINED
+func (m *Matcher) Relate(target common.WellFormedName) Relation {
+	var rel relator
+	for i, attr := range attributes {
+		r := m.values[i].compare(target.Get(attr))
+		if r == DISJOINT {
+			return DISJOINT
+		}
+		rel.add(r)
+	}
+	return rel.relation()
+}
+
+// relator combines the relations of the attributes of two names.
+type relator struct {
+	disjoint, notEqual, notSubset, notSuperset bool
+}
+
+func (rel *relator) add(r Relation) {
+	switch r {
+	case DISJOINT:
+		rel.disjoint = true
+	case EQUAL:
+	case SUBSET:
+		rel.notEqual, rel.notSuperset = true, true
+	case SUPERSET:
+		rel.notEqual, rel.notSubset = true, true
+	default:
+		rel.notEqual, rel.notSubset, rel.notSuperset = true, true, true
+	}
+}
+
+// relation returns the relation of the names, as Relate defines it
+func (rel relator) relation() Relation {
+	switch {
+	case rel.disjoint:
+		return DISJOINT
+	case !rel.notEqual:
+		return EQUAL
+	case !rel.notSuperset:
+		return SUPERSET
+	case !rel.notSubset:
+		return SUBSET
+	}
+	return UNDEFINED
+}
@@ -0,0 +1,156 @@
+package matching
+
+import (
+	"io/ioutil"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/knqyf263/go-cpe/common"
+	"github.com/knqyf263/go-cpe/naming"
+)
+
+func TestCompile(t *testing.T) {
+	vectors := []struct {
+		source   string
+		target   string
+		expected map[string]Relation
+	}{{
+		source: `cpe:2.3:a:microsoft:internet_explorer:8.*:sp?:*:*:*:*:*:*`,
+		target: `cpe:2.3:a:microsoft:internet_explorer:8.0.6001:sp2:*:*:*:*:*:*`,
+	}, {
+		source: `cpe:2.3:a:*:???:-:*:*:*:*:*:*:*`,
+		target: `cpe:2.3:a:\*:\?\?:1:*:*:*:*:*:*:*`,
+	}, {
+		source: `cpe:2.3:a:Microsoft:IE:*:*:*:*:*:*:*:*`,
+		target: `cpe:2.3:a:microsoft:ie:*:*:*:*:*:*:*:*`,
+	}, {
+		source: `cpe:2.3:a:?ller:*bar:-:*:*:*:*:*:*:*`,
+		target: `cpe:2.3:a:müller:foobar:-:*:*:*:*:*:*:*`,
+	}, {
+		source: `cpe:2.3:o:linux:linux_kernel:2.6.*:*:*:*:*:*:*:*`,
+		target: `cpe:2.3:o:linux:linux_kernel:2.6.3?:*:*:*:*:*:*:*`,
+	}, {
+		source: `cpe:2.3:a:vendor:product:1.0:*:*:*:*:*:*:*`,
+		target: `cpe:2.3:a:vendor:product:*:-:*:*:*:*:*:*`,
+		expected: map[string]Relation{
+			common.AttributePart:      EQUAL,
+			common.AttributeVendor:    EQUAL,
+			common.AttributeProduct:   EQUAL,
+			common.AttributeVersion:   SUBSET,
+			common.AttributeUpdate:    SUPERSET,
+			common.AttributeEdition:   EQUAL,
+			common.AttributeLanguage:  EQUAL,
+			common.AttributeSwEdition: EQUAL,
+			common.AttributeTargetSw:  EQUAL,
+			common.AttributeTargetHw:  EQUAL,
+			common.AttributeOther:     EQUAL,
+		},
+	},
+	}
+
+	for i, v := range vectors {
+		source, err := naming.UnbindFS(v.source)
+		if err != nil {
+			t.Fatalf("test %d, UnbindFS(source): %v", i, err)
+		}
+		target, err := naming.UnbindFS(v.target)
+		if err != nil {
+			t.Fatalf("test %d, UnbindFS(target): %v", i, err)
+		}
+		m := Compile(source)
+		actual := m.Compare(target)
+		if !reflect.DeepEqual(actual, CompareWFNs(source, target)) {
+			t.Errorf("test %d, Compare: got %v, want %v", i, actual, CompareWFNs(source, target))
+		}
+		if v.expected != nil && !reflect.DeepEqual(actual, v.expected) {
+			t.Errorf("test %d, Compare: got %v, want %v", i, actual, v.expected)
+		}
+		for _, attr := range common.Attributes() {
+			if r := m.CompareValue(attr, target.Get(attr)); r != actual[attr] {
+				t.Errorf("test %d, CompareValue(%s): got %v, want %v", i, attr, r, actual[attr])
+			}
+		}
+		if m.IsDisjoint(target) != IsDisjoint(source, target) {
+			t.Errorf("test %d, IsDisjoint: got %v, want %v", i, m.IsDisjoint(target), IsDisjoint(source, target))
+		}
+		if m.IsEqual(target) != IsEqual(source, target) {
+			t.Errorf("test %d, IsEqual: got %v, want %v", i, m.IsEqual(target), IsEqual(source, target))
+		}
+		if m.IsSubset(target) != IsSubset(source, target) {
+			t.Errorf("test %d, IsSubset: got %v, want %v", i, m.IsSubset(target), IsSubset(source, target))
+		}
+		if m.IsSuperset(target) != IsSuperset(source, target) {
+			t.Errorf("test %d, IsSuperset: got %v, want %v", i, m.IsSuperset(target), IsSuperset(source, target))
+		}
+		var relation Relation
+		switch {
+		case IsDisjoint(source, target):
+			relation = DISJOINT
+		case IsEqual(source, target):
+			relation = EQUAL
+		case IsSuperset(source, target):
+			relation = SUPERSET
+		case IsSubset(source, target):
+			relation = SUBSET
+		default:
+			relation = UNDEFINED
+		}
+		if r := Relate(source, target); r != relation {
+			t.Errorf("test %d, Relate: got %v, want %v", i, r, relation)
+		}
+	}
+
+	if r := Compile(common.WellFormedName{}).CompareValue("invalid", "foo"); r != UNDEFINED {
+		t.Errorf("CompareValue(invalid): got %v, want %v", r, UNDEFINED)
+	}
+}
+
+var corpusName = regexp.MustCompile(`name="(cpe:2\.3:[^"]+)"`)
+
+// loadTargets returns the WFNs of the dictionary test data.
+func loadTargets(b *testing.B) []common.WellFormedName {
+	data, err := ioutil.ReadFile("../dictionary/testdata/dictionary.xml")
+	if err != nil {
+		b.Fatal(err)
+	}
+	var targets []common.WellFormedName
+	for _, m := range corpusName.FindAllSubmatch(data, -1) {
+		wfn, err := naming.UnbindFS(string(m[1]))
+		if err != nil {
+			b.Fatal(err)
+		}
+		targets = append(targets, wfn)
+	}
+	return targets
+}
+
+var benchSource = common.WellFormedName{
+	"part":    "a",
+	"vendor":  "apache",
+	"product": "tomcat",
+	"version": `8\.0\.*`,
+}
+
+func BenchmarkCompareWFNs(b *testing.B) {
+	targets := loadTargets(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, target := range targets {
+			IsSuperset(benchSource, target)
+		}
+	}
+}
+
+func BenchmarkMatcher(b *testing.B) {
+	targets := loadTargets(b)
+	m := Compile(benchSource)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, target := range targets {
+			m.IsSuperset(target)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package matching
+
+import (
+	"github.com/knqyf263/go-cpe/common"
+	"github.com/knqyf263/go-cpe/version"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrInvalidRange is returned when a version range has conflicting bounds
+	ErrInvalidRange = errors.New("Invalid version range")
+	// ErrNotComparable is returned when a version attribute is not a literal
+	ErrNotComparable = errors.New("Version is not comparable")
+)
+
+// VersionComparator compares two version strings.
+// Compare returns a negative number if a < b, zero if a == b
+// and a positive number if a > b.
+// Every version.Comparator is a VersionComparator.
+type VersionComparator interface {
+	Compare(a, b string) (int, error)
+}
+
+// VersionComparatorFunc is an adapter to allow the use of ordinary functions as VersionComparator.
+type VersionComparatorFunc func(a, b string) (int, error)
+
+// Compare calls f(a, b)
+func (f VersionComparatorFunc) Compare(a, b string) (int, error) {
+	return f(a, b)
+}
+
+// DefaultVersionComparator is used when no VersionComparator is given.
+// Use version.ForEcosystem to compare versions according to the ecosystem
+// the CPE came from.
+var DefaultVersionComparator VersionComparator = version.Generic
+
+// VersionRange represents version bounds as used by NVD
+// (versionStartIncluding, versionStartExcluding, versionEndIncluding, versionEndExcluding).
+// An empty bound is unbounded.
+type VersionRange struct {
+	StartIncluding string
+	StartExcluding string
+	EndIncluding   string
+	EndExcluding   string
+}
+
+// IsEmpty returns true if no bound is set
+func (r VersionRange) IsEmpty() bool {
+	return r == VersionRange{}
+}
+
+// Validate checks that a start and an end bound are each set at most once
+func (r VersionRange) Validate() error {
+	if r.StartIncluding != "" && r.StartExcluding != "" {
+		return errors.Wrap(ErrInvalidRange, "both start including and start excluding are set")
+	}
+	if r.EndIncluding != "" && r.EndExcluding != "" {
+		return errors.Wrap(ErrInvalidRange, "both end including and end excluding are set")
+	}
+	return nil
+}
+
+// Contains tests if the version is within the range.
+// @param v unquoted version string
+// @param cmp VersionComparator, or nil for DefaultVersionComparator
+// @return true if the version satisfies every bound
+func (r VersionRange) Contains(v string, cmp VersionComparator) (bool, error) {
+	if err := r.Validate(); err != nil {
+		return false, err
+	}
+	if cmp == nil {
+		cmp = DefaultVersionComparator
+	}
+	bounds := []struct {
+		bound string
+		ok    func(int) bool
+	}{
+		{r.StartIncluding, func(c int) bool { return c >= 0 }},
+		{r.StartExcluding, func(c int) bool { return c > 0 }},
+		{r.EndIncluding, func(c int) bool { return c <= 0 }},
+		{r.EndExcluding, func(c int) bool { return c < 0 }},
+	}
+	for _, b := range bounds {
+		if b.bound == "" {
+			continue
+		}
+		c, err := cmp.Compare(v, b.bound)
+		if err != nil {
+			return false, errors.Wrapf(err, "Failed to compare %s with %s", v, b.bound)
+		}
+		if !b.ok(c) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// IsApplicable tests if the source Well Formed Name, restricted by a version range,
+// applies to the target Well Formed Name.
+// Every attribute other than version must be a superset or equal.
+// If the range is empty, version is compared as in IsSuperset. Otherwise the target
+// version must be a literal within the range, and a source version other than ANY
+// must also be a superset or equal.
+// @param source Source WFN, e.g. NVD criteria
+// @param target Target WFN, e.g. an installed platform
+// @param r VersionRange applied to the target version
+// @param cmp VersionComparator, or nil for DefaultVersionComparator
+// @return true if the source applies to the target
+func IsApplicable(source, target common.WellFormedName, r VersionRange, cmp VersionComparator) (bool, error) {
+	if err := r.Validate(); err != nil {
+		return false, err
+	}
+	results := CompareWFNs(source, target)
+	for attr, result := range results {
+		if attr == common.AttributeVersion && !r.IsEmpty() {
+			continue
+		}
+		if result != SUPERSET && result != EQUAL {
+			return false, nil
+		}
+	}
+	if r.IsEmpty() {
+		return true, nil
+	}
+
+	if lv, ok := source.Get(common.AttributeVersion).(common.LogicalValue); !ok || !lv.IsANY() {
+		if result := results[common.AttributeVersion]; result != SUPERSET && result != EQUAL {
+			return false, nil
+		}
+	}
+
+	// The target version must be known to be compared with the bounds.
+	v, ok := target.Get(common.AttributeVersion).(string)
+	if !ok || common.ContainsWildcards(v) {
+		return false, nil
+	}
+	return r.Contains(common.Unquote(v), cmp)
+}
+
+// CompareVersions compares the version attributes of two Well Formed Names.
+// @param source Source WFN
+// @param target Target WFN
+// @param cmp VersionComparator, or nil for DefaultVersionComparator
+// @return the comparison of the unquoted source and target versions, or
+// ErrNotComparable if either version is a logical value or contains wildcards
+func CompareVersions(source, target common.WellFormedName, cmp VersionComparator) (int, error) {
+	if cmp == nil {
+		cmp = DefaultVersionComparator
+	}
+	s, ok := source.Get(common.AttributeVersion).(string)
+	if !ok || common.ContainsWildcards(s) {
+		return 0, errors.Wrapf(ErrNotComparable, "source version: %v", source.Get(common.AttributeVersion))
+	}
+	t, ok := target.Get(common.AttributeVersion).(string)
+	if !ok || common.ContainsWildcards(t) {
+		return 0, errors.Wrapf(ErrNotComparable, "target version: %v", target.Get(common.AttributeVersion))
+	}
+	return cmp.Compare(common.Unquote(s), common.Unquote(t))
+}
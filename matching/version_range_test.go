@@ -0,0 +1,214 @@
+package matching
+
+import (
+	"testing"
+
+	"github.com/knqyf263/go-cpe/common"
+	"github.com/knqyf263/go-cpe/version"
+	"github.com/pkg/errors"
+)
+
+func TestVersionRangeContains(t *testing.T) {
+	vectors := []struct {
+		version  string
+		r        VersionRange
+		expected bool
+		wantErr  error
+	}{{
+		version:  "1.10",
+		r:        VersionRange{StartIncluding: "1.9"},
+		expected: true,
+	}, {
+		version:  "1.9",
+		r:        VersionRange{StartExcluding: "1.9"},
+		expected: false,
+	}, {
+		version:  "1.9",
+		r:        VersionRange{EndIncluding: "1.9"},
+		expected: true,
+	}, {
+		version:  "1.9",
+		r:        VersionRange{EndExcluding: "1.9"},
+		expected: false,
+	}, {
+		version:  "2.4.1",
+		r:        VersionRange{StartIncluding: "2.4", EndExcluding: "2.4.10"},
+		expected: true,
+	}, {
+		version:  "2.0.0",
+		r:        VersionRange{EndIncluding: "2.0"},
+		expected: true,
+	}, {
+		version:  "2.0",
+		r:        VersionRange{StartIncluding: "2.0.0"},
+		expected: true,
+	}, {
+		version:  "1.0",
+		r:        VersionRange{},
+		expected: true,
+	}, {
+		version: "1.0",
+		r:       VersionRange{StartIncluding: "1.0", StartExcluding: "0.9"},
+		wantErr: ErrInvalidRange,
+	},
+	}
+
+	for i, v := range vectors {
+		actual, err := v.r.Contains(v.version, nil)
+		if errors.Cause(err) != v.wantErr {
+			t.Errorf("test %d, Error: got %v, want %v", i, errors.Cause(err), v.wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		if actual != v.expected {
+			t.Errorf("test %d, Result: got %v, want %v", i, actual, v.expected)
+		}
+	}
+}
+
+func TestIsApplicable(t *testing.T) {
+	any, _ := common.NewLogicalValue("ANY")
+	criteria := common.NewWellFormedName()
+	criteria.Set(common.AttributePart, "a")
+	criteria.Set(common.AttributeVendor, "apache")
+	criteria.Set(common.AttributeProduct, "http_server")
+
+	pinned := common.NewWellFormedName()
+	pinned.Set(common.AttributePart, "a")
+	pinned.Set(common.AttributeVendor, "apache")
+	pinned.Set(common.AttributeProduct, "http_server")
+	pinned.Set(common.AttributeVersion, `2\.4\.1`)
+
+	vectors := []struct {
+		source   common.WellFormedName
+		version  interface{}
+		product  string
+		r        VersionRange
+		expected bool
+	}{{
+		source:   criteria,
+		version:  `2\.4\.10`,
+		product:  "http_server",
+		r:        VersionRange{StartIncluding: "2.4.0", EndExcluding: "2.4.9"},
+		expected: false,
+	}, {
+		source:   criteria,
+		version:  `2\.4\.8`,
+		product:  "http_server",
+		r:        VersionRange{StartIncluding: "2.4.0", EndExcluding: "2.4.9"},
+		expected: true,
+	}, {
+		source:   criteria,
+		version:  `2\.4\.8`,
+		product:  "tomcat",
+		r:        VersionRange{StartIncluding: "2.4.0", EndExcluding: "2.4.9"},
+		expected: false,
+	}, {
+		source:   criteria,
+		version:  any,
+		product:  "http_server",
+		r:        VersionRange{EndExcluding: "2.4.9"},
+		expected: false,
+	}, {
+		source:   criteria,
+		version:  `2\.4\.*`,
+		product:  "http_server",
+		r:        VersionRange{EndExcluding: "2.4.9"},
+		expected: false,
+	}, {
+		source:   pinned,
+		version:  `2\.4\.1`,
+		product:  "http_server",
+		r:        VersionRange{},
+		expected: true,
+	}, {
+		source:   pinned,
+		version:  `2\.4\.2`,
+		product:  "http_server",
+		r:        VersionRange{EndExcluding: "2.4.9"},
+		expected: false,
+	},
+	}
+
+	for i, v := range vectors {
+		target := common.NewWellFormedName()
+		target.Set(common.AttributePart, "a")
+		target.Set(common.AttributeVendor, "apache")
+		target.Set(common.AttributeProduct, v.product)
+		target.Set(common.AttributeVersion, v.version)
+		actual, err := IsApplicable(v.source, target, v.r, nil)
+		if err != nil {
+			t.Errorf("test %d, Unexpected error: %s", i, err)
+			continue
+		}
+		if actual != v.expected {
+			t.Errorf("test %d, Result: got %v, want %v", i, actual, v.expected)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	any, _ := common.NewLogicalValue("ANY")
+	vectors := []struct {
+		source   interface{}
+		target   interface{}
+		cmp      VersionComparator
+		expected int
+		wantErr  error
+	}{{
+		source:   `1\.10`,
+		target:   `1\.9`,
+		expected: 1,
+	}, {
+		source:   `1\.0\.0`,
+		target:   `1\.0`,
+		expected: 0,
+	}, {
+		source:   `1\.0\.0`,
+		target:   `1\.0`,
+		cmp:      version.ForEcosystem("debian"),
+		expected: 1,
+	}, {
+		source:   `1\.0\~rc1`,
+		target:   `1\.0`,
+		cmp:      version.ForEcosystem("debian"),
+		expected: -1,
+	}, {
+		source:  any,
+		target:  `1\.0`,
+		wantErr: ErrNotComparable,
+	}, {
+		source:  `1\.0`,
+		target:  `1\.*`,
+		wantErr: ErrNotComparable,
+	},
+	}
+
+	for i, v := range vectors {
+		source := common.NewWellFormedName()
+		source.Set(common.AttributeVersion, v.source)
+		target := common.NewWellFormedName()
+		target.Set(common.AttributeVersion, v.target)
+		actual, err := CompareVersions(source, target, v.cmp)
+		if errors.Cause(err) != v.wantErr {
+			t.Errorf("test %d, Error: got %v, want %v", i, errors.Cause(err), v.wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		if sign(actual) != v.expected {
+			t.Errorf("test %d, Result: got %v, want %v", i, actual, v.expected)
+		}
+	}
+}
+
+func sign(i int) int {
+	if i < 0 {
+		return -1
+	}
+	if i > 0 {
+		return 1
+	}
+	return 0
+}
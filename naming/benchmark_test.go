@@ -0,0 +1,126 @@
+package naming
+
+import (
+	"io/ioutil"
+	"regexp"
+	"testing"
+
+	"github.com/knqyf263/go-cpe/common"
+)
+
+var corpusName = regexp.MustCompile(`name="(cpe:[^"]+)"`)
+
+// The sinks keep the compiler from optimizing away the benchmarked calls.
+var (
+	sinkName string
+	sinkWFN  common.WellFormedName
+)
+
+// loadCorpus returns the URIs and formatted strings of the dictionary test data.
+func loadCorpus(b *testing.B) (uris, fss []string) {
+	data, err := ioutil.ReadFile("../dictionary/testdata/dictionary.xml")
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, m := range corpusName.FindAllSubmatch(data, -1) {
+		switch name := string(m[1]); DetectBinding(name) {
+		case BindingURI:
+			uris = append(uris, name)
+		case BindingFS:
+			fss = append(fss, name)
+		}
+	}
+	if len(uris) == 0 || len(fss) == 0 {
+		b.Fatal("empty corpus")
+	}
+	return uris, fss
+}
+
+func loadCorpusWFNs(b *testing.B) []common.WellFormedName {
+	_, fss := loadCorpus(b)
+	var wfns []common.WellFormedName
+	for _, fs := range fss {
+		wfn, err := UnbindFS(fs)
+		if err != nil {
+			b.Fatal(err)
+		}
+		wfns = append(wfns, wfn)
+	}
+	return wfns
+}
+
+func BenchmarkUnbindURI(b *testing.B) {
+	uris, _ := loadCorpus(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, uri := range uris {
+			wfn, err := UnbindURI(uri)
+			if err != nil {
+				b.Fatal(err)
+			}
+			sinkWFN = wfn
+		}
+	}
+}
+
+func BenchmarkUnbindFS(b *testing.B) {
+	_, fss := loadCorpus(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, fs := range fss {
+			wfn, err := UnbindFS(fs)
+			if err != nil {
+				b.Fatal(err)
+			}
+			sinkWFN = wfn
+		}
+	}
+}
+
+func BenchmarkBindToURI(b *testing.B) {
+	wfns := loadCorpusWFNs(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, wfn := range wfns {
+			sinkName = BindToURI(wfn)
+		}
+	}
+}
+
+func BenchmarkBindToFS(b *testing.B) {
+	wfns := loadCorpusWFNs(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, wfn := range wfns {
+			sinkName = BindToFS(wfn)
+		}
+	}
+}
+
+func BenchmarkAppendURI(b *testing.B) {
+	wfns := loadCorpusWFNs(b)
+	buf := make([]byte, 0, 256)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, wfn := range wfns {
+			buf = AppendURI(buf[:0], wfn)
+		}
+	}
+}
+
+func BenchmarkAppendFS(b *testing.B) {
+	wfns := loadCorpusWFNs(b)
+	buf := make([]byte, 0, 256)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, wfn := range wfns {
+			buf = AppendFS(buf[:0], wfn)
+		}
+	}
+}
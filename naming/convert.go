@@ -0,0 +1,90 @@
+package naming
+
+import (
+	"github.com/knqyf263/go-cpe/common"
+)
+
+// Loss describes an attribute whose value changed when converting between bindings.
+type Loss struct {
+	Attribute string
+	// Before is the value unbound from the source binding
+	Before interface{}
+	// After is the value unbound from the converted name, or nil if
+	// the value cannot be represented in the target binding
+	After interface{}
+}
+
+// Conversion is the result of converting a CPE name from one binding to another.
+type Conversion struct {
+	Result string
+	Losses []Loss
+}
+
+// Lossless returns true if unbinding Result gives back every attribute value
+func (c Conversion) Lossless() bool {
+	return len(c.Losses) == 0
+}
+
+// Affected returns the attributes whose values were not preserved
+func (c Conversion) Affected() []string {
+	var attrs []string
+	for _, l := range c.Losses {
+		attrs = append(attrs, l.Attribute)
+	}
+	return attrs
+}
+
+// ConvertURIToFS converts a URI to a formatted string.
+// @param uri String representing the URI
+// @return Conversion holding the formatted string and the attributes that were not preserved
+func ConvertURIToFS(uri string) (Conversion, error) {
+	wfn, err := UnbindURI(uri)
+	if err != nil {
+		return Conversion{}, err
+	}
+	return convert(wfn, BindToFS, UnbindFS), nil
+}
+
+// ConvertFSToURI converts a formatted string to a URI.
+// URIs are case insensitive, so values with uppercase letters are reported
+// as losses, as are extended attributes that cannot be packed into the
+// edition component.
+// @param fs Formatted string
+// @return Conversion holding the URI and the attributes that were not preserved
+func ConvertFSToURI(fs string) (Conversion, error) {
+	wfn, err := UnbindFS(fs)
+	if err != nil {
+		return Conversion{}, err
+	}
+	return convert(wfn, BindToURI, UnbindURI), nil
+}
+
+// convert binds wfn and checks which attribute values survive unbinding the result.
+// If the result cannot be unbound at all, each attribute is checked on its own.
+func convert(wfn common.WellFormedName, bind func(common.WellFormedName) string,
+	unbind func(string) (common.WellFormedName, error)) Conversion {
+	c := Conversion{Result: bind(wfn)}
+	converted, err := unbind(c.Result)
+	for _, attr := range common.Attributes() {
+		before := wfn.Get(attr)
+		if err == nil {
+			if after := converted.Get(attr); after != before {
+				c.Losses = append(c.Losses, Loss{Attribute: attr, Before: before, After: after})
+			}
+			continue
+		}
+
+		single := common.WellFormedName{}
+		if part, ok := wfn[common.AttributePart]; ok {
+			single[common.AttributePart] = part
+		}
+		single[attr] = before
+		after, serr := unbind(bind(single))
+		if serr != nil {
+			c.Losses = append(c.Losses, Loss{Attribute: attr, Before: before})
+		} else if after.Get(attr) != before {
+			c.Losses = append(c.Losses, Loss{Attribute: attr, Before: before, After: after.Get(attr)})
+		}
+	}
+	return c
+}
@@ -0,0 +1,94 @@
+package naming
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/knqyf263/go-cpe/common"
+	"github.com/pkg/errors"
+)
+
+func TestConvertURIToFS(t *testing.T) {
+	vectors := []struct {
+		s        string
+		expected string
+		affected []string
+		wantErr  error
+	}{{
+		s:        "cpe:/a:microsoft:internet_explorer%01%01%01%01:8%02:beta",
+		expected: "cpe:2.3:a:microsoft:internet_explorer????:8*:beta:*:*:*:*:*:*",
+	}, {
+		s:        "cpe:/a:foo%5cbar:big%24money_2010:::~~special~ipod_touch~80gb~",
+		expected: `cpe:2.3:a:foo\\bar:big\$money_2010:*:*:*:*:special:ipod_touch:80gb:*`,
+	}, {
+		s:        "cpe:/a:Microsoft:Internet_Explorer:8.0.6001:-:~-~-~-~-~-",
+		expected: "cpe:2.3:a:microsoft:internet_explorer:8.0.6001:-:-:*:-:-:-:-",
+	}, {
+		s:       "cpe:/a:micro%02soft",
+		wantErr: common.ErrParse,
+	},
+	}
+
+	for i, v := range vectors {
+		c, err := ConvertURIToFS(v.s)
+		if errors.Cause(err) != v.wantErr {
+			t.Errorf("test %d, Error: got %v, want %v", i, err, v.wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		if c.Result != v.expected {
+			t.Errorf("test %d, Result: got %v, want %v", i, c.Result, v.expected)
+		}
+		if c.Lossless() != (len(v.affected) == 0) || !reflect.DeepEqual(c.Affected(), v.affected) {
+			t.Errorf("test %d, Affected: got %v, want %v", i, c.Affected(), v.affected)
+		}
+	}
+}
+
+func TestConvertFSToURI(t *testing.T) {
+	vectors := []struct {
+		s        string
+		expected string
+		losses   []Loss
+		wantErr  error
+	}{{
+		s:        "cpe:2.3:a:microsoft:internet_explorer:8.0.6001:beta:*:*:*:*:*:*",
+		expected: "cpe:/a:microsoft:internet_explorer:8.0.6001:beta",
+	}, {
+		s:        `cpe:2.3:a:hp:insight_diagnostics:7.4.0.1570:-:*:*:online:win2003:x64:\~x`,
+		expected: "cpe:/a:hp:insight_diagnostics:7.4.0.1570:-:~~online~win2003~x64~%7ex",
+	}, {
+		s:        "cpe:2.3:a:Microsoft:internet_explorer:8.0.6001:beta:*:*:*:*:*:*",
+		expected: "cpe:/a:Microsoft:internet_explorer:8.0.6001:beta",
+		losses: []Loss{
+			{Attribute: common.AttributeVendor, Before: "Microsoft", After: "microsoft"},
+		},
+	}, {
+		s:        "cpe:2.3:a:m\xc3\xbcller:foo:1.0:*:*:*:*:*:*:Bar",
+		expected: "cpe:/a:m%c3%bcller:foo:1.0::~~~~~Bar",
+		losses: []Loss{
+			{Attribute: common.AttributeOther, Before: "Bar", After: "bar"},
+		},
+	}, {
+		s:       "cpe:2.3:a:microsoft",
+		wantErr: common.ErrParse,
+	},
+	}
+
+	for i, v := range vectors {
+		c, err := ConvertFSToURI(v.s)
+		if errors.Cause(err) != v.wantErr {
+			t.Errorf("test %d, Error: got %v, want %v", i, err, v.wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		if c.Result != v.expected {
+			t.Errorf("test %d, Result: got %v, want %v", i, c.Result, v.expected)
+		}
+		if c.Lossless() != (len(v.losses) == 0) || !reflect.DeepEqual(c.Losses, v.losses) {
+			t.Errorf("test %d, Losses: got %#v, want %#v", i, c.Losses, v.losses)
+		}
+	}
+}
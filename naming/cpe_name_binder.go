@@ -1,294 +1,255 @@
 package naming
 
 import (
-	"fmt"
-	"strings"
+	"unicode/utf8"
 
 	"github.com/knqyf263/go-cpe/common"
 )
 
+// uriAttributes are the attributes bound to the seven components of a URI, in order.
+// The extended attributes are packed into the edition component.
+var uriAttributes = []string{common.AttributePart, common.AttributeVendor, common.AttributeProduct,
+	common.AttributeVersion, common.AttributeUpdate, common.AttributeEdition, common.AttributeLanguage}
+
+// fsAttributes are the attributes bound to the eleven fields of a formatted string, in order.
+var fsAttributes = []string{common.AttributePart, common.AttributeVendor, common.AttributeProduct,
+	common.AttributeVersion, common.AttributeUpdate, common.AttributeEdition, common.AttributeLanguage,
+	common.AttributeSwEdition, common.AttributeTargetSw, common.AttributeTargetHw, common.AttributeOther}
+
+// packedAttributes are the extended attributes packed after the edition in a URI.
+var packedAttributes = []string{common.AttributeSwEdition, common.AttributeTargetSw,
+	common.AttributeTargetHw, common.AttributeOther}
+
 // BindToURI a {@link WellFormedName} object to a URI.
 // @param w WellFormedName to be bound to URI
 // @return URI binding of WFN
-func BindToURI(w common.WellFormedName) (uri string) {
-	// Initialize the output with the CPE v2.2 URI prefix.
-	uri = "cpe:/"
+func BindToURI(w common.WellFormedName) string {
+	var buf [128]byte
+	return string(AppendURI(buf[:0], w))
+}
 
-	// Define the attributes that correspond to the seven components in a v2.2. CPE.
-	attributes := []string{"part", "vendor", "product", "version", "update", "edition", "language"}
+// AppendURI appends the URI binding of w to dst and returns the extended buffer.
+// It does not allocate if dst has enough capacity, so a buffer can be reused
+// to bind many names.
+// @param dst buffer to append to
+// @param w WellFormedName to be bound to URI
+// @return dst with the URI binding of WFN appended
+func AppendURI(dst []byte, w common.WellFormedName) []byte {
+	// Initialize the output with the CPE v2.2 URI prefix.
+	dst = append(dst, "cpe:/"...)
+	prefix := len(dst)
 
-	for _, a := range attributes {
-		v := ""
-		if a == "edition" {
-			// Call the pack() helper function to compute the proper binding for the edition element.
-			ed := bindValueForURI(w.Get(common.AttributeEdition))
-			swEd := bindValueForURI(w.Get(common.AttributeSwEdition))
-			targetSw := bindValueForURI(w.Get(common.AttributeTargetSw))
-			targetHw := bindValueForURI(w.Get(common.AttributeTargetHw))
-			other := bindValueForURI(w.Get(common.AttributeOther))
-			v = pack(ed, swEd, targetSw, targetHw, other)
+	for _, a := range uriAttributes {
+		if a == common.AttributeEdition {
+			dst = packURI(dst, w)
 		} else {
-			// Get the value for a in w, then bind to a string
-			// for inclusion in the URI.
-			v = bindValueForURI(w.Get(a))
+			// Get the value for a in w, then bind it for inclusion in the URI.
+			dst = appendValueURI(dst, w.Get(a))
 		}
-		// Append v to the URI then add a colon.
-		uri += v + ":"
+		dst = append(dst, ':')
 	}
-	return strings.TrimRight(uri, ":")
+	// Trailing blank components are dropped.
+	for len(dst) > prefix && dst[len(dst)-1] == ':' {
+		dst = dst[:len(dst)-1]
+	}
+	return dst
 }
 
 // BindToFS is top-level function used to bind WFN w to formatted string.
 // @param w WellFormedName to bind
 // @return Formatted String
-func BindToFS(w common.WellFormedName) (fs string) {
+func BindToFS(w common.WellFormedName) string {
+	var buf [128]byte
+	return string(AppendFS(buf[:0], w))
+}
+
+// AppendFS appends the formatted string binding of w to dst and returns the extended buffer.
+// It does not allocate if dst has enough capacity, so a buffer can be reused
+// to bind many names.
+// @param dst buffer to append to
+// @param w WellFormedName to bind
+// @return dst with the formatted string appended
+func AppendFS(dst []byte, w common.WellFormedName) []byte {
 	// Initialize the output with the CPE v2.3 string prefix.
-	fs = "cpe:2.3:"
-	attributes := []string{"part", "vendor", "product", "version",
-		"update", "edition", "language", "sw_edition", "target_sw",
-		"target_hw", "other"}
-	for _, a := range attributes {
-		v := bindValueForFS(w.Get(a))
-		fs += v
-		if a != common.AttributeOther {
-			fs += ":"
+	dst = append(dst, "cpe:2.3:"...)
+	for i, a := range fsAttributes {
+		if i > 0 {
+			dst = append(dst, ':')
 		}
+		dst = appendValueFS(dst, w.Get(a))
 	}
-	return fs
+	return dst
+}
+
+// BindToWFN binds WFN w to its text form, as defined in NISTIR 7695 section 5.4.
+// It is the inverse of UnbindWFN, and equivalent to w.String().
+// @param w WellFormedName to bind
+// @return WFN text, e.g. wfn:[part="a", vendor="microsoft", ...]
+func BindToWFN(w common.WellFormedName) string {
+	return w.String()
 }
 
-// bindValueForURI converts a string to the proper string for including in a CPE v2.2-conformant URI.
+// appendValueURI appends the proper string for including s in a CPE v2.2-conformant URI.
 // The logical value ANY binds to the blank in the 2.2-conformant URI.
-// @param s string to be converted
-// @return converted string
-func bindValueForURI(s interface{}) string {
-	if lv, ok := s.(common.LogicalValue); ok {
-		// The value NA binds to a blank.
-		if lv.IsANY() {
-			return ""
-		}
+// @param dst buffer to append to
+// @param s value to be converted
+// @return dst with the converted value appended
+func appendValueURI(dst []byte, s interface{}) []byte {
+	switch v := s.(type) {
+	case common.LogicalValue:
 		// The value NA binds to a single hyphen.
-		if lv.IsNA() {
-			return "-"
+		if v.IsNA() {
+			return append(dst, '-')
 		}
+	case string:
+		return appendTransformedURI(dst, v)
 	}
-
-	if str, ok := s.(string); ok {
-		return transformForURI(str)
-	}
-	return ""
+	// The value ANY binds to a blank.
+	return dst
 }
 
-// bindValueForFS converts the value v to its proper string representation for insertion to formatted string.
+// appendValueFS appends the proper string representation of v for insertion to formatted string.
+// @param dst buffer to append to
 // @param v value to convert
-// @return Formatted value
-func bindValueForFS(v interface{}) string {
-	if lv, ok := v.(common.LogicalValue); ok {
-		// The value NA binds to a asterisk.
-		if lv.IsANY() {
-			return "*"
+// @return dst with the formatted value appended
+func appendValueFS(dst []byte, v interface{}) []byte {
+	switch t := v.(type) {
+	case common.LogicalValue:
+		// The value ANY binds to a asterisk.
+		if t.IsANY() {
+			return append(dst, '*')
 		}
 		// The value NA binds to a single hyphen.
-		if lv.IsNA() {
-			return "-"
+		if t.IsNA() {
+			return append(dst, '-')
 		}
+	case string:
+		return appendQuotedFS(dst, t)
 	}
-	if str, ok := v.(string); ok {
-		return processQuotedChars(str)
-	}
-	return ""
+	return dst
 }
 
-// Inspect each character in string s.  Certain nonalpha characters pass
+// appendQuotedFS inspects each character in string s.  Certain nonalpha characters pass
 // thru without escaping into the result, but most retain escaping.
-// @param s
-// @return
-func processQuotedChars(s string) (result string) {
-	idx := 0
-	for idx < len(s) {
-		c := s[idx : idx+1]
-		if c == "\\" {
-			// escaped characters are examined.
-			nextchr := s[idx+1 : idx+2]
+// A quoted multi-byte character is copied byte by byte, which keeps its escaping.
+// @param dst buffer to append to
+// @param s quoted WFN value
+// @return dst with the formatted value appended
+func appendQuotedFS(dst []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' {
+			// unquoted characters pass thru unharmed.
+			dst = append(dst, c)
+			continue
+		}
+		if i == len(s)-1 {
+			// a trailing backslash quotes nothing, so bind it as a quoted backslash.
+			return append(dst, '\\', '\\')
+		}
+		// escaped characters are examined.
+		i++
+		switch next := s[i]; next {
+		case '.', '-', '_':
 			// the period, hyphen and underscore pass unharmed.
-			if nextchr == "." || nextchr == "-" || nextchr == "_" {
-				result += nextchr
-				idx += 2
-				continue
-			} else {
-				// all others retain escaping.
-				result += "\\" + nextchr
-				idx += 2
-				continue
-			}
+			dst = append(dst, next)
+		default:
+			// all others retain escaping.
+			dst = append(dst, '\\', next)
 		}
-		// unquoted characters pass thru unharmed.
-		result += c
-		idx = idx + 1
 	}
-	return result
+	return dst
 }
 
-// transformForURI scans an input string and performs the following transformations:
+// appendTransformedURI scans an input string and performs the following transformations:
 // - Pass alphanumeric characters thru untouched
 // - Percent-encode quoted non-alphanumerics as needed
 // - Unquoted special characters are mapped to their special forms
+// - Percent-encode non-ASCII characters as UTF-8
+// @param dst buffer to append to
 // @param s string to be transformed
-// @return transformed string
-func transformForURI(s string) (result string) {
-	idx := 0
-	for idx < len(s) {
-		// Get the idx'th character of s.
-		thischar := s[idx : idx+1]
-		if common.IsAlphanum(thischar) {
-			result += thischar
-			idx++
-			continue
-		}
-		// Check for escape character.
-		if thischar == "\\" {
-			idx++
-			nxtchar := s[idx : idx+1]
-			result += pctEncode(nxtchar)
-			idx++
-			continue
-		}
-		// Bind the unquoted '?' special character to "%01".
-		if thischar == "?" {
-			result += "%01"
-		}
-		// Bind the unquoted '*' special character to "%02".
-		if thischar == "*" {
-			result += "%02"
+// @return dst with the transformed string appended
+func appendTransformedURI(dst []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case common.IsAlpha(rune(c)) || (c >= '0' && c <= '9') || c == '_':
+			dst = append(dst, c)
+		case c == '\\':
+			// Check for escape character.
+			i++
+			if i == len(s) {
+				// a trailing backslash quotes nothing, so bind it as a quoted backslash.
+				return appendPctEncoded(dst, c)
+			}
+			// The bytes of a quoted non-ASCII character that follow
+			// the first one are encoded by the last case.
+			dst = appendPctEncoded(dst, s[i])
+		case c == '?':
+			// Bind the unquoted '?' special character to "%01".
+			dst = append(dst, "%01"...)
+		case c == '*':
+			// Bind the unquoted '*' special character to "%02".
+			dst = append(dst, "%02"...)
+		case c >= utf8.RuneSelf:
+			// Non-ASCII characters are percent-encoded even if unquoted.
+			dst = appendPctEncoded(dst, c)
 		}
-		idx++
-
 	}
-	return result
+	return dst
 }
 
+const hexDigits = "0123456789abcdef"
+
 // pctEncode returns the appropriate percent-encoding of character c.
 // Certain characters are returned without encoding.
 // @param c the single character string to be encoded
 // @return the percent encoded string
 func pctEncode(c string) string {
-	if c == "!" {
-		return "%21"
-	}
-	if c == "\"" {
-		return "%22"
-	}
-	if c == "#" {
-		return "%23"
-	}
-	if c == "$" {
-		return "%24"
+	dst := make([]byte, 0, 3*len(c))
+	for i := 0; i < len(c); i++ {
+		dst = appendPctEncoded(dst, c[i])
 	}
-	if c == "%" {
-		return "%25"
-	}
-	if c == "&" {
-		return "%26"
-	}
-	if c == "'" {
-		return "%27"
-	}
-	if c == "(" {
-		return "%28"
-	}
-	if c == ")" {
-		return "%29"
-	}
-	if c == "*" {
-		return "%2a"
-	}
-	if c == "+" {
-		return "%2b"
-	}
-	if c == "," {
-		return "%2c"
-	}
-	// bound without encoding.
-	if c == "-" {
-		return c
-	}
-	// bound without encoding.
-	if c == "." {
-		return c
-	}
-	if c == "/" {
-		return "%2f"
-	}
-	if c == ":" {
-		return "%3a"
-	}
-	if c == ";" {
-		return "%3b"
-	}
-	if c == "<" {
-		return "%3c"
-	}
-	if c == "=" {
-		return "%3d"
-	}
-	if c == ">" {
-		return "%3e"
-	}
-	if c == "?" {
-		return "%3f"
-	}
-	if c == "@" {
-		return "%40"
-	}
-	if c == "[" {
-		return "%5b"
-	}
-	if c == "\\" {
-		return "%5c"
-	}
-	if c == "]" {
-		return "%5d"
-	}
-	if c == "^" {
-		return "%5e"
-	}
-	if c == "`" {
-		return "%60"
-	}
-	if c == "{" {
-		return "%7b"
-	}
-	if c == "|" {
-		return "%7c"
-	}
-	if c == "}" {
-		return "%7d"
-	}
-	if c == "~" {
-		return "%7e"
-	}
-	// Shouldn't reach here, return original character
-	return c
+	return string(dst)
+}
+
+// appendPctEncoded appends the percent-encoding of byte c.  ASCII punctuation
+// other than the hyphen, period and underscore, and every byte of a non-ASCII
+// character, is encoded; other bytes are appended without encoding.
+func appendPctEncoded(dst []byte, c byte) []byte {
+	if c == '-' || c == '.' || c == '_' || (c < utf8.RuneSelf && !isPunct(c)) {
+		return append(dst, c)
+	}
+	return append(dst, '%', hexDigits[c>>4], hexDigits[c&0xf])
 }
 
-/**
- * Packs the values of the five arguments into the single
- * edition component.  If all the values are blank, the
- * function returns a blank.
- * @param ed edition string
- * @param swEd software edition string
- * @param tSw target software string
- * @param tHw target hardware string
- * @param oth other edition information string
- * @return the packed string, or blank
- */
-func pack(ed, swEd, tSw, tHw, oth string) string {
-	if swEd == "" && tSw == "" && tHw == "" && oth == "" {
+// isPunct returns true if c is ASCII punctuation.
+func isPunct(c byte) bool {
+	return (c >= 0x21 && c <= 0x2f) || (c >= 0x3a && c <= 0x40) || (c >= 0x5b && c <= 0x60) || (c >= 0x7b && c <= 0x7e)
+}
+
+// packURI appends the values of the edition and the extended attributes of w,
+// packed into the single edition component.  If the extended attributes are
+// all blank, only the edition is appended.
+// @param dst buffer to append to
+// @param w WellFormedName
+// @return dst with the packed string, or the edition, appended
+func packURI(dst []byte, w common.WellFormedName) []byte {
+	start := len(dst)
+	// Pack the five values into a single string
+	// prefixed and internally delimited with the tilde.
+	dst = append(dst, '~')
+	dst = appendValueURI(dst, w.Get(common.AttributeEdition))
+	ed := len(dst)
+	for _, a := range packedAttributes {
+		dst = append(dst, '~')
+		dst = appendValueURI(dst, w.Get(a))
+	}
+	if len(dst) == ed+4 {
 		// All the extended attributes are blank, so don't do
-		// any packing, just return ed.
-		return ed
+		// any packing, just keep ed.
+		copy(dst[start:], dst[start+1:ed])
+		dst = dst[:ed-1]
 	}
-	// Otherwise, pack the five values into a single string
-	// prefixed and internally delimited with the tilde.
-	return fmt.Sprintf("~%s~%s~%s~%s~%s", ed, swEd, tSw, tHw, oth)
+	return dst
 }
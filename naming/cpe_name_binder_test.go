@@ -66,6 +66,21 @@ func TestBindToURI(t *testing.T) {
 			"product": `\{\|\}\~\a`,
 		},
 		expected: "cpe:/a:%60:%7b%7c%7d%7ea",
+	}, {
+		// a trailing backslash binds as a quoted backslash
+		w: common.WellFormedName{
+			"part":   "a",
+			"vendor": `foo\`,
+		},
+		expected: "cpe:/a:foo%5c",
+	}, {
+		// non-ASCII characters are percent-encoded as UTF-8
+		w: common.WellFormedName{
+			"part":    "a",
+			"vendor":  `m\üller`,
+			"product": `\日\本`,
+		},
+		expected: "cpe:/a:m%c3%bcller:%e6%97%a5%e6%9c%ac",
 	},
 	}
 
@@ -115,6 +130,21 @@ func TestBindToFS(t *testing.T) {
 			"vendor": 1,
 		},
 		expected: "cpe:2.3:a::*:*:*:*:*:*:*:*:*",
+	}, {
+		// a trailing backslash binds as a quoted backslash
+		w: common.WellFormedName{
+			"part":   "a",
+			"vendor": `foo\`,
+		},
+		expected: `cpe:2.3:a:foo\\:*:*:*:*:*:*:*:*:*`,
+	}, {
+		// non-ASCII characters stay quoted
+		w: common.WellFormedName{
+			"part":    "a",
+			"vendor":  `m\üller`,
+			"product": `\日\本`,
+		},
+		expected: `cpe:2.3:a:m\üller:\日\本:*:*:*:*:*:*:*:*`,
 	},
 	}
 
@@ -125,3 +155,168 @@ func TestBindToFS(t *testing.T) {
 		}
 	}
 }
+
+func TestAppendFS(t *testing.T) {
+	vectors := []struct {
+		dst      string
+		w        common.WellFormedName
+		expected string
+	}{{
+		w: common.WellFormedName{
+			"part":    "a",
+			"vendor":  "microsoft",
+			"product": "internet_explorer",
+			"version": `8\.0\.6001`,
+		},
+		expected: "cpe:2.3:a:microsoft:internet_explorer:8.0.6001:*:*:*:*:*:*:*",
+	}, {
+		dst: "cpe:2.3:a:foo:*:*:*:*:*:*:*:*:*\n",
+		w: common.WellFormedName{
+			"part":   "a",
+			"vendor": `foo\\`,
+		},
+		expected: "cpe:2.3:a:foo:*:*:*:*:*:*:*:*:*\ncpe:2.3:a:foo\\\\:*:*:*:*:*:*:*:*:*",
+	},
+	}
+
+	for i, v := range vectors {
+		actual := string(AppendFS([]byte(v.dst), v.w))
+		if actual != v.expected {
+			t.Errorf("test %d, Result: got %v, want %v", i, actual, v.expected)
+		}
+	}
+}
+
+func TestAppendURI(t *testing.T) {
+	vectors := []struct {
+		dst      string
+		w        common.WellFormedName
+		expected string
+	}{{
+		w: common.WellFormedName{
+			"part":    "a",
+			"vendor":  "microsoft",
+			"product": "internet_explorer",
+			"version": `8\.0\.6001`,
+		},
+		expected: "cpe:/a:microsoft:internet_explorer:8.0.6001",
+	}, {
+		dst: "cpe:/a:foo\n",
+		w: common.WellFormedName{
+			"part":     "a",
+			"vendor":   "hp",
+			"edition":  na,
+			"other":    "x64",
+			"language": any,
+		},
+		expected: "cpe:/a:foo\ncpe:/a:hp::::~-~~~~x64",
+	},
+	}
+
+	for i, v := range vectors {
+		actual := string(AppendURI([]byte(v.dst), v.w))
+		if actual != v.expected {
+			t.Errorf("test %d, Result: got %v, want %v", i, actual, v.expected)
+		}
+	}
+}
+
+func TestAppendAllocs(t *testing.T) {
+	w, err := UnbindFS(`cpe:2.3:a:\$0.99_kindle_books_project:\$0.99_kindle_books:6:*:*:*:*:android:*:*`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 0, 256)
+	if n := testing.AllocsPerRun(100, func() { buf = AppendFS(buf[:0], w) }); n != 0 {
+		t.Errorf("AppendFS: got %v allocs, want 0", n)
+	}
+	if n := testing.AllocsPerRun(100, func() { buf = AppendURI(buf[:0], w) }); n != 0 {
+		t.Errorf("AppendURI: got %v allocs, want 0", n)
+	}
+}
+
+func TestBindToWFN(t *testing.T) {
+	vectors := []struct {
+		w        common.WellFormedName
+		expected string
+	}{{
+		w: common.WellFormedName{
+			"part":     "a",
+			"vendor":   "microsoft",
+			"product":  "internet_explorer",
+			"version":  `8\.*`,
+			"update":   "sp?",
+			"edition":  na,
+			"language": any,
+		},
+		expected: `wfn:[part="a", vendor="microsoft", product="internet_explorer", version="8\.*", update="sp?", edition=NA, language=ANY, sw_edition=ANY, target_sw=ANY, target_hw=ANY, other=ANY]`,
+	}, {
+		w: common.WellFormedName{
+			"part":    "a",
+			"vendor":  `foo\\bar`,
+			"product": `big\$money_2010`,
+			"other":   `\"quoted\"`,
+		},
+		expected: `wfn:[part="a", vendor="foo\\bar", product="big\$money_2010", version=ANY, update=ANY, edition=ANY, language=ANY, sw_edition=ANY, target_sw=ANY, target_hw=ANY, other="\"quoted\""]`,
+	},
+	}
+
+	for i, v := range vectors {
+		actual := BindToWFN(v.w)
+		if actual != v.expected {
+			t.Errorf("test %d, Result: got %v, want %v", i, actual, v.expected)
+		}
+	}
+}
+
+func TestWFNRoundTrip(t *testing.T) {
+	vectors := []struct {
+		s       string
+		binding Binding
+	}{{
+		s:       "cpe:/a:microsoft:internet_explorer%01%01%01%01:8%02:beta",
+		binding: BindingURI,
+	}, {
+		s:       "cpe:/a:foo%5cbar:big%24money_2010:::~~special~ipod_touch~80gb~",
+		binding: BindingURI,
+	}, {
+		s:       "cpe:/a:hp:insight_diagnostics:7.4.0.1570:-:~~online~win2003~x64~",
+		binding: BindingURI,
+	}, {
+		s:       `cpe:2.3:a:microsoft:internet_explorer:8.0.6001:beta:*:*:*:*:*:*`,
+		binding: BindingFS,
+	}, {
+		s:       `cpe:2.3:a:foo\"bar:\"quoted\":??1.*:-:*:en-us:special:ipod_touch:80gb:\\\:`,
+		binding: BindingFS,
+	}, {
+		s:       `cpe:2.3:o:linux:linux_kernel:2.6.0:*:*:*:*:*:*:*`,
+		binding: BindingFS,
+	},
+	}
+
+	for i, v := range vectors {
+		wfn, binding, err := Parse(v.s)
+		if err != nil {
+			t.Errorf("test %d, Error: %v", i, err)
+			continue
+		}
+		if binding != v.binding {
+			t.Errorf("test %d, Binding: got %v, want %v", i, binding, v.binding)
+		}
+		text := BindToWFN(wfn)
+		actual, err := UnbindWFN(text)
+		if err != nil {
+			t.Errorf("test %d, Error: %s: %v", i, text, err)
+			continue
+		}
+		if BindToWFN(actual) != text {
+			t.Errorf("test %d, WFN: got %v, want %v", i, BindToWFN(actual), text)
+		}
+		if BindToURI(actual) != BindToURI(wfn) {
+			t.Errorf("test %d, URI: got %v, want %v", i, BindToURI(actual), BindToURI(wfn))
+		}
+		if BindToFS(actual) != BindToFS(wfn) {
+			t.Errorf("test %d, FS: got %v, want %v", i, BindToFS(actual), BindToFS(wfn))
+		}
+	}
+}
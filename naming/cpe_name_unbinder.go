@@ -2,6 +2,7 @@ package naming
 
 import (
 	"strings"
+	"unicode/utf8"
 
 	"github.com/knqyf263/go-cpe/common"
 	"github.com/pkg/errors"
@@ -14,47 +15,32 @@ func UnbindURI(uri string) (common.WellFormedName, error) {
 	if err := common.ValidateURI(uri); err != nil {
 		return nil, errors.Wrap(err, "Failed to validate uri")
 	}
-	result := common.WellFormedName{}
-	for i := 0; i < 8; i++ {
-		v := getCompURI(uri, i)
-		d, err := decode(v)
-		if i != 6 && err != nil {
-			return nil, err
-		}
-		switch i {
-		case 1:
-			err = result.Set(common.AttributePart, d)
-		case 2:
-			err = result.Set(common.AttributeVendor, d)
-		case 3:
-			err = result.Set(common.AttributeProduct, d)
-		case 4:
-			err = result.Set(common.AttributeVersion, d)
-		case 5:
-			err = result.Set(common.AttributeUpdate, d)
-		case 6:
+	comps := tokenizeURI(uri)
+	result := make(common.WellFormedName, len(fsAttributes))
+	for i := 1; i < 8; i++ {
+		v := comps.values[i]
+		base := comps.offsets[i]
+		attr := uriAttributes[i-1]
+		if attr == common.AttributeEdition && v != "" && v[0] == '~' {
 			// Special handling for edition component.
-			// Unpack edition if needed.
-			if v == "" || v == "-" || v[0] != '~' {
-				// Just a logical value or a non-packed value.
-				// So unbind to legacy edition, leaving other
-				// extended attributes unspecified.
-				err = result.Set(common.AttributeEdition, d)
-			} else {
-				// We have five values packed together here.
-				if result, err = unpack(v, result); err != nil {
-					return nil, err
-				}
+			// We have five values packed together here.
+			if _, err := unpack(v, result); err != nil {
+				return nil, common.SetPosition(err, uri, i, base, true)
 			}
-		case 7:
-			err = result.Set(common.AttributeLanguage, d)
+			continue
 		}
+		// Just a logical value or a non-packed value.
+		// A non-packed edition unbinds to legacy edition, leaving other
+		// extended attributes unspecified.
+		d, err := decode(v)
 		if err != nil {
-			return nil, err
+			return nil, common.SetPosition(err, uri, i, base, true)
+		}
+		if err = result.Set(attr, d); err != nil {
+			return nil, common.SetPosition(err, uri, i, base, false)
 		}
 	}
 	return result, nil
-
 }
 
 // UnbindFS is a top level function to unbind a formatted string to WFN.
@@ -66,81 +52,224 @@ func UnbindFS(fs string) (common.WellFormedName, error) {
 	if err := common.ValidateFS(fs); err != nil {
 		return nil, err
 	}
-	// Initialize empty WFN
-	result := common.NewWellFormedName()
+	comps := tokenizeFS(fs)
+	// Every attribute is set below.
+	result := make(common.WellFormedName, len(fsAttributes))
 	// The cpe scheme is the 0th component, the cpe version is the 1st.
 	// So we start parsing at the 2nd component.
 	for a := 2; a != 13; a++ {
-		// Get the a'th string field.
-		s := getCompFS(fs, a)
+		s := comps.values[a]
+		base := comps.offsets[a]
 		// Unbind the string.
 		v, err := unbindValueFS(s)
 		if err != nil {
-			return nil, err
+			return nil, common.SetPosition(err, fs, a, base, true)
 		}
-
 		// Set the value of the corresponding attribute.
-		switch a {
-		case 2:
-			err = result.Set(common.AttributePart, v)
-			break
-		case 3:
-			err = result.Set(common.AttributeVendor, v)
-			break
-		case 4:
-			err = result.Set(common.AttributeProduct, v)
-			break
-		case 5:
-			err = result.Set(common.AttributeVersion, v)
-			break
-		case 6:
-			err = result.Set(common.AttributeUpdate, v)
-			break
-		case 7:
-			err = result.Set(common.AttributeEdition, v)
-			break
-		case 8:
-			err = result.Set(common.AttributeLanguage, v)
-			break
-		case 9:
-			err = result.Set(common.AttributeSwEdition, v)
-			break
-		case 10:
-			err = result.Set(common.AttributeTargetSw, v)
-			break
-		case 11:
-			err = result.Set(common.AttributeTargetHw, v)
-			break
-		case 12:
-			err = result.Set(common.AttributeOther, v)
-			break
+		if err = result.Set(fsAttributes[a-2], v); err != nil {
+			return nil, common.SetPosition(err, fs, a, base, false)
 		}
-		if err != nil {
-			return nil, err
+	}
+	return result, nil
+}
+
+// UnbindWFN is a top level function to unbind the text form of a WFN, as
+// returned by WellFormedName.String, e.g.
+// wfn:[part="a", vendor="microsoft", product="internet_explorer", version=ANY].
+// Attributes may appear in any order and omitted attributes are ANY.
+// A part of ANY is omitted, as in common.NewWellFormedName.
+// @param w WFN text to unbind
+// @return WellFormedName
+func UnbindWFN(w string) (common.WellFormedName, error) {
+	if !strings.HasPrefix(strings.ToLower(w), "wfn:[") {
+		err := common.NewParseError(w, common.ReasonBadPrefix, 0, "Error: WFN must start with 'wfn:['")
+		err.Component = 0
+		return nil, err
+	}
+	result := common.NewWellFormedName()
+	seen := map[string]bool{}
+	idx := skipSpaces(w, len("wfn:["))
+	closed := idx < len(w) && w[idx] == ']'
+	if closed {
+		idx++
+	}
+	for component := 0; !closed; component++ {
+		// attribute name
+		start := idx
+		for idx < len(w) && (common.IsAlpha(rune(w[idx])) || w[idx] == '_') {
+			idx++
+		}
+		attr := strings.ToLower(w[start:idx])
+		if attr == "" {
+			return nil, wfnSyntaxError(w, component, start, "expected attribute name")
+		}
+		if !common.IsValidAttribute(attr) {
+			return nil, wfnSyntaxError(w, component, start, "unknown attribute: %s", w[start:idx])
+		}
+		if seen[attr] {
+			return nil, wfnSyntaxError(w, component, start, "duplicate attribute: %s", attr)
+		}
+		seen[attr] = true
+
+		idx = skipSpaces(w, idx)
+		if idx >= len(w) || w[idx] != '=' {
+			return nil, wfnSyntaxError(w, component, idx, "expected '=' after %s", attr)
 		}
+		idx = skipSpaces(w, idx+1)
+
+		// value, either a quoted string or a logical value
+		start = idx
+		var value interface{}
+		if idx < len(w) && w[idx] == '"' {
+			end := -1
+			for j := idx + 1; j < len(w); j++ {
+				if w[j] == '\\' {
+					j++
+					continue
+				}
+				if w[j] == '"' {
+					end = j
+					break
+				}
+			}
+			if end == -1 {
+				return nil, wfnSyntaxError(w, component, start, "unterminated value of %s", attr)
+			}
+			value = w[idx+1 : end]
+			idx = end + 1
+			start++
+		} else {
+			for idx < len(w) && common.IsAlpha(rune(w[idx])) {
+				idx++
+			}
+			lv, err := common.NewLogicalValue(w[start:idx])
+			if err != nil {
+				return nil, wfnSyntaxError(w, component, start, "value of %s must be a quoted string, ANY or NA", attr)
+			}
+			value = lv
+		}
+		if lv, ok := value.(common.LogicalValue); ok && attr == common.AttributePart && lv.IsANY() {
+			delete(result, attr)
+		} else if err := result.Set(attr, value); err != nil {
+			_, quoted := value.(string)
+			return nil, common.SetPosition(err, w, component, start, quoted)
+		}
+
+		idx = skipSpaces(w, idx)
+		if idx < len(w) && w[idx] == ',' {
+			idx = skipSpaces(w, idx+1)
+			continue
+		}
+		if idx < len(w) && w[idx] == ']' {
+			idx++
+			closed = true
+			continue
+		}
+		return nil, wfnSyntaxError(w, component, idx, "expected ',' or ']' after %s", attr)
+	}
+	if idx = skipSpaces(w, idx); idx != len(w) {
+		return nil, wfnSyntaxError(w, len(seen), idx, "unexpected characters after ']'")
 	}
 	return result, nil
 }
 
+func wfnSyntaxError(w string, component, offset int, format string, args ...interface{}) error {
+	err := common.NewParseError(w, common.ReasonSyntax, offset, format, args...)
+	err.Component = component
+	return err
+}
+
+// skipSpaces returns the index of the first non-space character of s at or after idx.
+func skipSpaces(s string, idx int) int {
+	for idx < len(s) && (s[idx] == ' ' || s[idx] == '\t' || s[idx] == '\n' || s[idx] == '\r') {
+		idx++
+	}
+	return idx
+}
+
+// maxComponents is the number of components of a formatted string, the
+// most of either binding.
+const maxComponents = 13
+
+// components holds the components of a URI or a formatted string and their
+// byte offsets, found in a single pass.  Components past the end of the name
+// are blank, with the length of the name as their offset.
+type components struct {
+	values  [maxComponents]string
+	offsets [maxComponents]int
+}
+
+// tokenizeURI splits a URI into its components.  The 0th component is the
+// URI scheme, and the leading slashes of the 1st component are skipped.
+// @param uri URI to split
+// @return components of uri
+func tokenizeURI(uri string) (c components) {
+	n := 0
+	start := 0
+	slash := strings.IndexByte(uri, '/')
+	if slash != -1 {
+		c.values[0] = uri[:slash]
+	}
+	for i := 0; i <= len(uri) && n < maxComponents; i++ {
+		if i < len(uri) && uri[i] != ':' {
+			continue
+		}
+		if n > 0 {
+			c.values[n] = uri[start:i]
+			c.offsets[n] = start
+		}
+		n++
+		start = i + 1
+		if n == 1 {
+			for start < len(uri) && uri[start] == '/' {
+				start++
+			}
+			i = start - 1
+		}
+	}
+	for ; n < maxComponents; n++ {
+		c.offsets[n] = len(uri)
+	}
+	return c
+}
+
+// tokenizeFS splits a formatted string into its fields.  The colon is the field
+// delimiter unless quoted by a backslash.
+// @param fs formatted string to split
+// @return components of fs
+func tokenizeFS(fs string) (c components) {
+	n := 0
+	start := 0
+	for i := 0; i <= len(fs) && n < maxComponents; i++ {
+		if i+1 < len(fs) && fs[i] == '\\' {
+			// skip the quoted character
+			i++
+			continue
+		}
+		if i < len(fs) && fs[i] != ':' {
+			continue
+		}
+		c.values[n] = fs[start:i]
+		c.offsets[n] = start
+		n++
+		start = i + 1
+	}
+	for ; n < maxComponents; n++ {
+		c.offsets[n] = len(fs)
+	}
+	return c
+}
+
 // getCompURI return the i'th component of the URI.
 // @param uri String representation of URI to retrieve components from.
 // @param i Index of component to return.
 // @return If i = 0, returns the URI scheme. Otherwise, returns the i'th
 // component of uri.
 func getCompURI(uri string, i int) string {
-	if i == 0 {
-		return uri[:strings.Index(uri, "/")]
-	}
-	sa := strings.Split(uri, ":")
-	// If requested component exceeds the number
-	// of components in URI, return blank
-	if i >= len(sa) {
+	if i < 0 || i >= maxComponents {
 		return ""
 	}
-	if i == 1 {
-		return strings.TrimLeft(sa[1], "/")
-	}
-	return sa[i]
+	return tokenizeURI(uri).values[i]
 }
 
 // Returns the i'th field of the formatted string.  The colon is the field
@@ -149,29 +278,19 @@ func getCompURI(uri string, i int) string {
 // @param i index of field to retrieve from fs.
 // @return value of index of formatted string
 func getCompFS(fs string, i int) string {
-	if i < 0 {
+	if i < 0 || i >= maxComponents {
 		return ""
 	}
-
-	for j := 0; j < i; j++ {
-		// return the substring from index 0 to the first occurence of an
-		// unescaped colon
-		colonIdx := common.GetUnescapedColonIndex(fs)
-		if colonIdx == 0 {
-			fs = ""
-			break
-		}
-		fs = fs[colonIdx+1:]
-	}
-	endIdx := common.GetUnescapedColonIndex(fs)
-	// If no colon is found, we are at the end of the formatted string,
-	// so just return what's left.
-	if endIdx == 0 {
-		return fs
-	}
-	return fs[:endIdx]
+	return tokenizeFS(fs).values[i]
 }
 
+// logicalAny and logicalNA are converted to interfaces once, so that
+// unbinding a logical value does not allocate.
+var (
+	logicalAny interface{} = common.LogicalValue{Any: true}
+	logicalNA  interface{} = common.LogicalValue{Na: true}
+)
+
 // Takes a string value and returns the appropriate logical value if string
 // is the bound form of a logical value.  If string is some general value
 // string, add quoting of non-alphanumerics as needed.
@@ -180,12 +299,10 @@ func getCompFS(fs string, i int) string {
 // @throws ParseException
 func unbindValueFS(s string) (interface{}, error) {
 	if s == "*" {
-		any, _ := common.NewLogicalValue("ANY")
-		return any, nil
+		return logicalAny, nil
 	}
 	if s == "-" {
-		na, _ := common.NewLogicalValue("NA")
-		return na, nil
+		return logicalNA, nil
 	}
 	result, err := addQuoting(s)
 	if err != nil {
@@ -194,73 +311,111 @@ func unbindValueFS(s string) (interface{}, error) {
 	return result, nil
 }
 
+// valueBuffer builds an unbound value from s in a single pass.  s is only
+// copied once a character of it is changed, so a value that needs no
+// changes is returned without allocating.
+type valueBuffer struct {
+	s      string
+	b      strings.Builder
+	copied bool
+}
+
+// keep keeps s[i:j] unchanged in the result.
+func (v *valueBuffer) keep(i, j int) {
+	if v.copied {
+		v.b.WriteString(v.s[i:j])
+	}
+}
+
+// change returns the builder to write the replacement for the characters starting at s[i].
+func (v *valueBuffer) change(i int) *strings.Builder {
+	if !v.copied {
+		// Quoting at most doubles the length of the rest of s.
+		v.b.Grow(len(v.s) + len(v.s) - i)
+		v.b.WriteString(v.s[:i])
+		v.copied = true
+	}
+	return &v.b
+}
+
+func (v *valueBuffer) String() string {
+	if v.copied {
+		return v.b.String()
+	}
+	return v.s
+}
+
 // Inspect each character in a string, copying quoted characters, with
 // their escaping, into the result.  Look for unquoted non alphanumerics
 // and if not "*" or "?", add escaping.
 // @param s
 // @return
 // @throws ParseException
-func addQuoting(s string) (result string, err error) {
+func addQuoting(s string) (string, error) {
+	out := valueBuffer{s: s}
 	idx := 0
 	embedded := false
 	for idx < len(s) {
-		c := s[idx : idx+1]
-		if common.IsAlphanum(c) || c == "_" {
+		c := s[idx]
+		switch {
+		case common.IsAlpha(rune(c)) || (c >= '0' && c <= '9') || c == '_':
 			// Alphanumeric characters pass untouched.
-			result += c
+			out.keep(idx, idx+1)
 			idx++
 			embedded = true
-			continue
-		}
-		if c == "\\" {
+		case c == '\\':
+			if idx == len(s)-1 {
+				return "", common.NewParseError(s, common.ReasonTrailingBackslash, idx, "Error! cannot have a trailing backslash in formatted string.")
+			}
 			// Anything quoted in the bound string stays quoted in the
 			// unbound string.
-			result += s[idx : idx+2]
-			idx += 2
+			_, next := utf8.DecodeRuneInString(s[idx+1:])
+			out.keep(idx, idx+1+next)
+			idx += 1 + next
 			embedded = true
-			continue
-		}
-		if c == "*" {
+		case c == '*':
 			// An unquoted asterisk must appear at the beginning or the end
 			// of the string.
-			if idx == 0 || idx == len(s)-1 {
-				result += c
-				idx = idx + 1
-				embedded = true
-				continue
+			if idx != 0 && idx != len(s)-1 {
+				return "", common.NewParseError(s, common.ReasonEmbeddedWildcard, idx, "Error! cannot have unquoted * embedded in formatted string.")
 			}
-			return "", errors.Wrap(common.ErrParse, "Error! cannot have unquoted * embedded in formatted string.")
-		}
-		if c == "?" {
+			out.keep(idx, idx+1)
+			idx++
+			embedded = true
+		case c == '?':
 			// An unquoted question mark must appear at the beginning or
 			// end of the string, or in a leading or trailing sequence.
-			// if  // ? legal at beginning or end
 			valid := false
 			if idx == 0 || idx == len(s)-1 {
 				// ? legal at beginning or end
 				valid = true
-			} else if !embedded && idx > 0 && s[idx-1:idx] == "?" {
+			} else if !embedded && s[idx-1] == '?' {
 				// embedded is false, so must be preceded by ?
 				valid = true
-			} else if embedded && len(s) >= idx+2 && s[idx+1:idx+2] == "?" {
+			} else if embedded && s[idx+1] == '?' {
 				// embedded is true, so must be followed by ?
 				valid = true
 			}
-
 			if !valid {
-				return "", errors.Wrap(common.ErrParse, "Error! cannot have unquoted ? embedded in formatted string.")
+				return "", common.NewParseError(s, common.ReasonEmbeddedWildcard, idx, "Error! cannot have unquoted ? embedded in formatted string.")
 			}
-			result += c
+			out.keep(idx, idx+1)
 			idx++
 			embedded = false
-			continue
+		default:
+			// All other characters, including non-ASCII ones, must be quoted.
+			r, size := utf8.DecodeRuneInString(s[idx:])
+			if r == utf8.RuneError && size == 1 {
+				return "", common.NewParseError(s, common.ReasonInvalidUTF8, idx, "Error! invalid UTF-8 in formatted string.")
+			}
+			b := out.change(idx)
+			b.WriteByte('\\')
+			b.WriteString(s[idx : idx+size])
+			idx += size
+			embedded = true
 		}
-		// All other characters must be quoted.
-		result += "\\" + c
-		idx++
-		embedded = true
 	}
-	return result, nil
+	return out.String(), nil
 }
 
 // decode scans a string and returns a copy with all percent-encoded characters
@@ -273,40 +428,61 @@ func addQuoting(s string) (result string, err error) {
 // @see CPENameBinder#pctEncode(java.lang.String)
 func decode(s string) (interface{}, error) {
 	if s == "" {
-		any, _ := common.NewLogicalValue("ANY")
-		return any, nil
+		return logicalAny, nil
 	}
 	if s == "-" {
-		na, _ := common.NewLogicalValue("NA")
-		return na, nil
+		return logicalNA, nil
 	}
 	// Start the scanning loop.
 	// Normalize: convert all uppercase letters to lowercase first.
 	s = strings.ToLower(s)
-	result := ""
+	out := valueBuffer{s: s}
 	idx := 0
 	embedded := false
 	for idx < len(s) {
 		// Get the idx'th character of s.
-		c := s[idx : idx+1]
+		c := s[idx]
 		// Deal with dot, hyphen, and tilde: decode with quoting.
-		if c == "." || c == "-" || c == "~" {
-			result += "\\" + c
+		if c == '.' || c == '-' || c == '~' {
+			b := out.change(idx)
+			b.WriteByte('\\')
+			b.WriteByte(c)
 			idx++
 			// a non-%01 encountered.
 			embedded = true
 			continue
 		}
-		if c != "%" {
-			result += c
+		if c < 0x21 || c > 0x7e {
+			// Only printable ASCII characters may appear in a URI.
+			return nil, common.NewParseError(s, common.ReasonNonPrintable, idx, "Illegal character in URI: %q", c)
+		}
+		if c != '%' {
+			out.keep(idx, idx+1)
 			idx++
 			// a non-%01 encountered.
 			embedded = true
 			continue
 		}
 		// We get here if we have a substring starting w/ '%'.
+		if idx+3 > len(s) {
+			return nil, common.NewParseError(s, common.ReasonBadPercentEncoding, idx, "Truncated form: %s", s[idx:])
+		}
 		form := s[idx : idx+3]
-		if form == "%01" {
+		d, ok := unhex(form)
+		switch {
+		case ok && d >= utf8.RuneSelf:
+			// A percent-encoded UTF-8 sequence decodes to a quoted non-ASCII character.
+			r, n, err := decodeUTF8(s[idx:])
+			if err != nil {
+				return nil, common.NewParseError(s, common.ReasonBadPercentEncoding, idx, "Invalid UTF-8 sequence: %s", s[idx:idx+n])
+			}
+			b := out.change(idx)
+			b.WriteByte('\\')
+			b.WriteRune(r)
+			idx += n
+			embedded = true
+			continue
+		case form == "%01":
 			valid := false
 			if idx == 0 || idx == len(s)-3 {
 				valid = true
@@ -315,85 +491,71 @@ func decode(s string) (interface{}, error) {
 			} else if embedded && len(s) >= idx+6 && s[idx+3:idx+6] == "%01" {
 				valid = true
 			}
-
-			if valid {
-				result += "?"
-				idx = idx + 3
-				continue
-			} else {
-				return nil, errors.Wrapf(common.ErrParse, "Error decoding string")
+			if !valid {
+				return nil, common.NewParseError(s, common.ReasonEmbeddedWildcard, idx, "Error decoding string")
 			}
-		} else if form == "%02" {
-			if idx == 0 || idx == len(s)-3 {
-				result += "*"
-			} else {
-				return nil, errors.Wrapf(common.ErrParse, "Error decoding string")
+			out.change(idx).WriteByte('?')
+			idx += 3
+			continue
+		case form == "%02":
+			if idx != 0 && idx != len(s)-3 {
+				return nil, common.NewParseError(s, common.ReasonEmbeddedWildcard, idx, "Error decoding string")
 			}
-		} else if form == "%21" {
-			result += `\!`
-		} else if form == "%22" {
-			result += `\"`
-		} else if form == "%23" {
-			result += `\#`
-		} else if form == "%24" {
-			result += `\$`
-		} else if form == "%25" {
-			result += `\%`
-		} else if form == "%26" {
-			result += `\&`
-		} else if form == "%27" {
-			result += `\'`
-		} else if form == "%28" {
-			result += `\(`
-		} else if form == "%29" {
-			result += `\)`
-		} else if form == "%2a" {
-			result += `\*`
-		} else if form == "%2b" {
-			result += `\+`
-		} else if form == "%2c" {
-			result += `\,`
-		} else if form == "%2f" {
-			result += `\/`
-		} else if form == "%3a" {
-			result += `\:`
-		} else if form == "%3b" {
-			result += `\;`
-		} else if form == "%3c" {
-			result += `\<`
-		} else if form == "%3d" {
-			result += `\=`
-		} else if form == "%3e" {
-			result += `\>`
-		} else if form == "%3f" {
-			result += `\?`
-		} else if form == "%40" {
-			result += `\@`
-		} else if form == "%5b" {
-			result += `\[`
-		} else if form == "%5c" {
-			result += `\\`
-		} else if form == "%5d" {
-			result += `\]`
-		} else if form == "%5e" {
-			result += `\^`
-		} else if form == "%60" {
-			result += "\\`"
-		} else if form == "%7b" {
-			result += `\{`
-		} else if form == "%7c" {
-			result += `\|`
-		} else if form == "%7d" {
-			result += `\}`
-		} else if form == "%7e" {
-			result += `\~`
-		} else {
-			return nil, errors.Wrapf(common.ErrParse, "Unknown form: %s", form)
+			out.change(idx).WriteByte('*')
+		case ok && isPunct(d) && d != '-' && d != '.' && d != '_':
+			// The forms produced by pctEncode decode to quoted punctuation.
+			b := out.change(idx)
+			b.WriteByte('\\')
+			b.WriteByte(d)
+		default:
+			return nil, common.NewParseError(s, common.ReasonBadPercentEncoding, idx, "Unknown form: %s", form)
 		}
-		idx = idx + 3
+		idx += 3
 		embedded = true
 	}
-	return result, nil
+	return out.String(), nil
+}
+
+// unhex returns the byte encoded by a percent-encoded form such as "%c3".
+func unhex(form string) (byte, bool) {
+	if len(form) != 3 || form[0] != '%' {
+		return 0, false
+	}
+	var b byte
+	for i := 1; i < 3; i++ {
+		switch c := form[i]; {
+		case c >= '0' && c <= '9':
+			b = b<<4 | (c - '0')
+		case c >= 'a' && c <= 'f':
+			b = b<<4 | (c - 'a' + 10)
+		case c >= 'A' && c <= 'F':
+			b = b<<4 | (c - 'A' + 10)
+		default:
+			return 0, false
+		}
+	}
+	return b, true
+}
+
+// decodeUTF8 decodes a single character from the percent-encoded UTF-8 sequence at the start of s.
+// @return the character, and the length of its encoding in s
+func decodeUTF8(s string) (rune, int, error) {
+	var buf [utf8.UTFMax]byte
+	k, n := 0, 0
+	for n+3 <= len(s) && k < len(buf) && !utf8.FullRune(buf[:k]) {
+		b, ok := unhex(s[n : n+3])
+		if !ok {
+			break
+		}
+		buf[k] = b
+		k++
+		n += 3
+	}
+	r, size := utf8.DecodeRune(buf[:k])
+	if (r == utf8.RuneError && size <= 1) || size != k {
+		return 0, n, common.ErrParse
+	}
+	return r, n, nil
 }
 
 // unpack unpacks the elements in s and sets the attributes in the given
@@ -402,21 +564,45 @@ func decode(s string) (interface{}, error) {
 // @param wfn WellFormedName
 // @return The augmented WellFormedName.
 func unpack(s string, wfn common.WellFormedName) (common.WellFormedName, error) {
-	// Set each component in the WFN.
-	editions := strings.SplitN(s[1:], "~", 5)
-	if len(editions) != 5 {
-		return nil, errors.Wrap(common.ErrParse, "editions must be 5")
+	if !strings.HasPrefix(s, "~") {
+		return nil, common.NewParseError(s, common.ReasonInvalidPacking, 0, "packed edition must start with ~")
+	}
+	// The fifth value holds the rest of s, including any further tildes.
+	if strings.Count(s, "~") < 5 {
+		return nil, common.NewParseError(s, common.ReasonInvalidPacking, 0, "editions must be 5")
 	}
-	attributes := []string{common.AttributeEdition, common.AttributeSwEdition, common.AttributeTargetSw,
+	attributes := [...]string{common.AttributeEdition, common.AttributeSwEdition, common.AttributeTargetSw,
 		common.AttributeTargetHw, common.AttributeOther}
+	// offset of the current packed value in s
+	offset := 1
 	for i, a := range attributes {
-		e, err := decode(editions[i])
+		end := len(s)
+		if i < len(attributes)-1 {
+			end = offset + strings.IndexByte(s[offset:], '~')
+		}
+		// Set each component in the WFN.
+		e, err := decode(s[offset:end])
 		if err != nil {
-			return nil, err
+			return nil, relocateParseError(common.SetAttribute(err, a), offset, true)
 		}
 		if err = wfn.Set(a, e); err != nil {
-			return nil, err
+			return nil, relocateParseError(err, offset, false)
 		}
+		offset = end + 1
 	}
 	return wfn, nil
 }
+
+// relocateParseError moves the offset of a ParseError in err, whose offset is relative
+// to a substring, to be relative to the enclosing string, as common.SetPosition does.
+func relocateParseError(err error, base int, exact bool) error {
+	var pe *common.ParseError
+	if errors.As(err, &pe) && pe.Component < 0 {
+		if exact && pe.Offset >= 0 {
+			pe.Offset += base
+		} else {
+			pe.Offset = base
+		}
+	}
+	return err
+}
@@ -89,14 +89,15 @@ func TestUnbindURI(t *testing.T) {
 		s:       "cpe:/a:micro%02soft",
 		wantErr: common.ErrParse,
 	}, {
-		s: "cpe:/a:micro%01%01:internet%02:%21%22%23%24%25%26%27%28%29%2a%2b%2c:beta-.::online%2f%3a%3b%3c%3d%3e%3f%40%5b%5c%5d%5e",
+		s: "cpe:/a:micro%01%01:internet%02:%21%22%23%24%25%26%27%28%29%2a%2b%2c:beta-.:online%2f%3a%3b%3c%3d%3e%3f%40%5b%5c%5d%5e:en-us",
 		expected: common.WellFormedName{
 			"part":     "a",
 			"vendor":   "micro??",
 			"product":  "internet*",
 			"version":  `\!\"\#\$\%\&\'\(\)\*\+\,`,
 			"update":   `beta\-\.`,
-			"language": `online\/\:\;\<\=\>\?\@\[\\\]\^`,
+			"edition":  `online\/\:\;\<\=\>\?\@\[\\\]\^`,
+			"language": `en\-us`,
 		},
 	}, {
 		s: "cpe:/a:%60:%7b%7c%7d%7ea",
@@ -105,6 +106,19 @@ func TestUnbindURI(t *testing.T) {
 			"vendor":  "\\`",
 			"product": `\{\|\}\~a`,
 		},
+	}, {
+		s: "cpe:/a:m%c3%bcller:%e6%97%a5%e6%9c%ac",
+		expected: common.WellFormedName{
+			"part":    "a",
+			"vendor":  `m\üller`,
+			"product": `\日\本`,
+		},
+	}, {
+		s:       "cpe:/a:m%c3ller:foo",
+		wantErr: common.ErrParse,
+	}, {
+		s:       "cpe:/a:m%ff:foo",
+		wantErr: common.ErrParse,
 	}, {
 		s:       "cpe:/a:hp:insight_diagnostics:7.4.0.1570::~~online~win2003",
 		wantErr: common.ErrParse,
@@ -127,6 +141,31 @@ func TestUnbindURI(t *testing.T) {
 		// empty part
 		s:       ` cpe:/:microsoft`,
 		wantErr: common.ErrParse,
+	}, {
+		// truncated percent-encoding
+		s:       "cpe:/a:microsoft:internet_explorer:8%2",
+		wantErr: common.ErrParse,
+	}, {
+		s:       "cpe:/a:microsoft:internet_explorer:8::~a~b~c~d~%",
+		wantErr: common.ErrParse,
+	}, {
+		// non-ASCII character
+		s:       "cpe:/a:micro\xffsoft",
+		wantErr: common.ErrParse,
+	}, {
+		// language with a wildcard
+		s: "cpe:/a:microsoft:internet_explorer:8.0:::en-%02",
+		expected: common.WellFormedName{
+			"part":     "a",
+			"vendor":   "microsoft",
+			"product":  "internet_explorer",
+			"version":  `8\.0`,
+			"language": `en\-*`,
+		},
+	}, {
+		// language is not a language tag
+		s:       "cpe:/a:microsoft:internet_explorer:8.0:::english_us",
+		wantErr: common.ErrParse,
 	},
 	}
 
@@ -244,6 +283,46 @@ func TestUnbindFS(t *testing.T) {
 		// empty part
 		s:       `cpe:2.3::2glux*:??com_sexypolling??:0.9.1:-:-:*:-:joomla\!:*:*`,
 		wantErr: common.ErrParse,
+	}, {
+		// trailing backslash
+		s:       `cpe:2.3:a:2glux:com_sexypolling:0.9.1:-:-:*:-:joomla\!:*:foo\`,
+		wantErr: common.ErrParse,
+	}, {
+		// non-ASCII characters are quoted
+		s: "cpe:2.3:a:m\u00fcller:\\\u65e5\u672c:1.0:*:*:*:*:*:*:*",
+		expected: common.WellFormedName{
+			"part":       "a",
+			"vendor":     `m\üller`,
+			"product":    `\日\本`,
+			"version":    `1\.0`,
+			"update":     any,
+			"edition":    any,
+			"sw_edition": any,
+			"target_sw":  any,
+			"target_hw":  any,
+			"other":      any,
+			"language":   any,
+		},
+	}, {
+		// invalid UTF-8
+		s:       "cpe:2.3:a:m\xfcller:foo:1.0:*:*:*:*:*:*:*",
+		wantErr: common.ErrParse,
+	}, {
+		// quoted backslash before a delimiter
+		s: `cpe:2.3:a:foo\\:bar\:baz:*:*:*:*:*:*:*:*`,
+		expected: common.WellFormedName{
+			"part":       "a",
+			"vendor":     `foo\\`,
+			"product":    `bar\:baz`,
+			"version":    any,
+			"update":     any,
+			"edition":    any,
+			"sw_edition": any,
+			"target_sw":  any,
+			"target_hw":  any,
+			"other":      any,
+			"language":   any,
+		},
 	},
 	}
 
@@ -377,6 +456,51 @@ func TestGetCompFS(t *testing.T) {
 	}
 }
 
+func TestTokenize(t *testing.T) {
+	vectors := []struct {
+		s       string
+		fs      bool
+		values  []string
+		offsets []int
+	}{{
+		s:       "cpe:/a:microsoft:ie:8.0",
+		values:  []string{"cpe:", "a", "microsoft", "ie", "8.0", "", ""},
+		offsets: []int{0, 5, 7, 17, 20, 23, 23},
+	}, {
+		s:       "cpe://a::ie",
+		values:  []string{"cpe:", "a", "", "ie", ""},
+		offsets: []int{0, 6, 8, 9, 11},
+	}, {
+		s:       `cpe:2.3:a:foo\\:b\:c:*`,
+		fs:      true,
+		values:  []string{"cpe", "2.3", "a", `foo\\`, `b\:c`, "*", ""},
+		offsets: []int{0, 4, 8, 10, 16, 21, 22},
+	}, {
+		s:       `cpe:2.3:a:foo\`,
+		fs:      true,
+		values:  []string{"cpe", "2.3", "a", `foo\`, ""},
+		offsets: []int{0, 4, 8, 10, 14},
+	},
+	}
+
+	for i, v := range vectors {
+		var c components
+		if v.fs {
+			c = tokenizeFS(v.s)
+		} else {
+			c = tokenizeURI(v.s)
+		}
+		for j := range v.values {
+			if c.values[j] != v.values[j] {
+				t.Errorf("test %d, value %d: got %q, want %q", i, j, c.values[j], v.values[j])
+			}
+			if c.offsets[j] != v.offsets[j] {
+				t.Errorf("test %d, offset %d: got %v, want %v", i, j, c.offsets[j], v.offsets[j])
+			}
+		}
+	}
+}
+
 func TestUnpack(t *testing.T) {
 	vectors := []struct {
 		s        string
@@ -436,3 +560,265 @@ func TestUnpack(t *testing.T) {
 	}
 
 }
+
+func TestUnbindParseError(t *testing.T) {
+	vectors := []struct {
+		s         string
+		uri       bool
+		component int
+		attribute string
+		offset    int
+		reason    common.ParseReason
+	}{{
+		s:         "cpe:2.3:a:foo*bar:baz:*:*:*:*:*:*:*:*",
+		component: 3,
+		offset:    13,
+		reason:    common.ReasonEmbeddedWildcard,
+	}, {
+		s:         "cpe:2.3:x:foo:bar:*:*:*:*:*:*:*:*",
+		component: 2,
+		attribute: common.AttributePart,
+		offset:    8,
+		reason:    common.ReasonInvalidPart,
+	}, {
+		s:         "cpe:2.3:a:foo:bar:*:*:*:*:*:*:*:**x",
+		component: 12,
+		offset:    33,
+		reason:    common.ReasonEmbeddedWildcard,
+	}, {
+		s:         "cpe:/a:foo:b%02ar",
+		uri:       true,
+		component: 3,
+		offset:    12,
+		reason:    common.ReasonEmbeddedWildcard,
+	}, {
+		s:         "cpe:/a:foo:bar:1.0::~a~b~c~d~e%02f",
+		uri:       true,
+		component: 6,
+		attribute: common.AttributeOther,
+		offset:    30,
+		reason:    common.ReasonEmbeddedWildcard,
+	}, {
+		s:         "cpe:/a:foo:bar:1.0::~a~b",
+		uri:       true,
+		component: 6,
+		offset:    20,
+		reason:    common.ReasonInvalidPacking,
+	}, {
+		s:         "cpe:/a:foo:bar:%zz",
+		uri:       true,
+		component: 4,
+		offset:    15,
+		reason:    common.ReasonBadPercentEncoding,
+	}, {
+		s:         "cpe:/x:foo",
+		uri:       true,
+		component: 1,
+		attribute: common.AttributePart,
+		offset:    5,
+		reason:    common.ReasonInvalidPart,
+	}, {
+		s:         "cpe:2.3:a:foo:bar:1.0:*:*:sp2:*:*:*:*",
+		component: 8,
+		attribute: common.AttributeLanguage,
+		offset:    26,
+		reason:    common.ReasonInvalidLanguage,
+	}, {
+		s:         "cpe:/a:foo:bar:1.0:::en-us-us",
+		uri:       true,
+		component: 7,
+		attribute: common.AttributeLanguage,
+		offset:    21,
+		reason:    common.ReasonInvalidLanguage,
+	},
+	}
+
+	for i, v := range vectors {
+		var err error
+		if v.uri {
+			_, err = UnbindURI(v.s)
+		} else {
+			_, err = UnbindFS(v.s)
+		}
+		var pe *common.ParseError
+		if !errors.As(err, &pe) {
+			t.Errorf("test %d, errors.As: got false for %v", i, err)
+			continue
+		}
+		if errors.Cause(err) != common.ErrParse {
+			t.Errorf("test %d, Cause: got %v, want %v", i, errors.Cause(err), common.ErrParse)
+		}
+		if pe.Input != v.s {
+			t.Errorf("test %d, Input: got %v, want %v", i, pe.Input, v.s)
+		}
+		if pe.Component != v.component {
+			t.Errorf("test %d, Component: got %v, want %v", i, pe.Component, v.component)
+		}
+		if pe.Attribute != v.attribute {
+			t.Errorf("test %d, Attribute: got %v, want %v", i, pe.Attribute, v.attribute)
+		}
+		if pe.Offset != v.offset {
+			t.Errorf("test %d, Offset: got %v, want %v", i, pe.Offset, v.offset)
+		}
+		if pe.Reason != v.reason {
+			t.Errorf("test %d, Reason: got %v, want %v", i, pe.Reason, v.reason)
+		}
+	}
+}
+
+func TestUnbindWFN(t *testing.T) {
+	vectors := []struct {
+		s        string
+		expected common.WellFormedName
+		wantErr  error
+	}{{
+		s: `wfn:[part="a", vendor="microsoft", product="internet_explorer", version="8\.0\.6001", update="beta", edition=ANY, language=ANY, sw_edition=ANY, target_sw=ANY, target_hw=ANY, other=ANY]`,
+		expected: common.WellFormedName{
+			"part":       "a",
+			"vendor":     "microsoft",
+			"product":    "internet_explorer",
+			"version":    `8\.0\.6001`,
+			"update":     "beta",
+			"edition":    any,
+			"sw_edition": any,
+			"target_sw":  any,
+			"target_hw":  any,
+			"other":      any,
+			"language":   any,
+		},
+	}, {
+		// any order, omitted attributes and extra whitespace
+		s: "wfn:[ update = NA,vendor=\"foo\\\"bar\" ,\tpart=\"o\" ]",
+		expected: common.WellFormedName{
+			"part":       "o",
+			"vendor":     `foo\"bar`,
+			"product":    any,
+			"version":    any,
+			"update":     na,
+			"edition":    any,
+			"sw_edition": any,
+			"target_sw":  any,
+			"target_hw":  any,
+			"other":      any,
+			"language":   any,
+		},
+	}, {
+		// part=ANY as printed for a WFN without part
+		s: `wfn:[part=ANY, vendor="microsoft"]`,
+		expected: common.WellFormedName{
+			"vendor":     "microsoft",
+			"product":    any,
+			"version":    any,
+			"update":     any,
+			"edition":    any,
+			"sw_edition": any,
+			"target_sw":  any,
+			"target_hw":  any,
+			"other":      any,
+			"language":   any,
+		},
+	}, {
+		s: `wfn:[]`,
+		expected: common.WellFormedName{
+			"vendor":     any,
+			"product":    any,
+			"version":    any,
+			"update":     any,
+			"edition":    any,
+			"sw_edition": any,
+			"target_sw":  any,
+			"target_hw":  any,
+			"other":      any,
+			"language":   any,
+		},
+	}, {
+		s:       `wfn:[part="a", part="o"]`,
+		wantErr: common.ErrParse,
+	}, {
+		s:       `wfn:[part="a", foo="bar"]`,
+		wantErr: common.ErrParse,
+	}, {
+		s:       `wfn:[part="a", vendor=microsoft]`,
+		wantErr: common.ErrParse,
+	}, {
+		s:       `wfn:[part="a", vendor="microsoft]`,
+		wantErr: common.ErrParse,
+	}, {
+		s:       `wfn:[part="a" vendor="microsoft"]`,
+		wantErr: common.ErrParse,
+	}, {
+		s:       `wfn:[part="a",]`,
+		wantErr: common.ErrParse,
+	}, {
+		s:       `wfn:[part="a"] x`,
+		wantErr: common.ErrParse,
+	}, {
+		s:       `wfn:[part="a", vendor="foo bar"]`,
+		wantErr: common.ErrParse,
+	}, {
+		s:       `cpe:2.3:a:microsoft:internet_explorer:8.0.6001:beta:*:*:*:*:*:*`,
+		wantErr: common.ErrParse,
+	},
+	}
+
+	for i, v := range vectors {
+		actual, err := UnbindWFN(v.s)
+		if errors.Cause(err) != v.wantErr {
+			t.Errorf("test %d, Error: got %v, want %v", i, err, v.wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		if len(actual) != len(v.expected) {
+			t.Errorf("test %d, Result: got %v, want %v", i, actual, v.expected)
+		}
+		for attr, value := range v.expected {
+			if actual.Get(attr) != value {
+				t.Errorf("test %d, %s: got %v, want %v", i, attr, actual.Get(attr), value)
+			}
+		}
+	}
+}
+
+func TestUnbindWFNParseError(t *testing.T) {
+	vectors := []struct {
+		s         string
+		component int
+		offset    int
+		reason    common.ParseReason
+	}{{
+		s:         `wfn:[part="a", vendor=foo]`,
+		component: 1,
+		offset:    22,
+		reason:    common.ReasonSyntax,
+	}, {
+		s:         `wfn:[part="a", vendor="foo bar"]`,
+		component: 1,
+		offset:    26,
+		reason:    common.ReasonWhitespace,
+	}, {
+		s:         `wfn:[part="x"]`,
+		component: 0,
+		offset:    11,
+		reason:    common.ReasonInvalidPart,
+	},
+	}
+
+	for i, v := range vectors {
+		_, err := UnbindWFN(v.s)
+		var pe *common.ParseError
+		if !errors.As(err, &pe) {
+			t.Errorf("test %d, errors.As: got false for %v", i, err)
+			continue
+		}
+		if pe.Component != v.component {
+			t.Errorf("test %d, Component: got %v, want %v", i, pe.Component, v.component)
+		}
+		if pe.Offset != v.offset {
+			t.Errorf("test %d, Offset: got %v, want %v", i, pe.Offset, v.offset)
+		}
+		if pe.Reason != v.reason {
+			t.Errorf("test %d, Reason: got %v, want %v", i, pe.Reason, v.reason)
+		}
+	}
+}
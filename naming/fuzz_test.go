@@ -0,0 +1,183 @@
+//go:build go1.18
+// +build go1.18
+
+package naming
+
+import (
+	"testing"
+	"unicode"
+
+	"github.com/knqyf263/go-cpe/common"
+)
+
+var fuzzURIs = []string{
+	"cpe:/a:microsoft:internet_explorer%01%01%01%01:?:beta",
+	"cpe:/a:microsoft:internet_explorer:8.%2a:sp%3f",
+	"cpe:/a:hp:insight_diagnostics:7.4.0.1570::~~online~win2003~x64~",
+	"cpe:/a:foo%5cbar:big%24money_2010%07:::~~special~ipod_touch~80gb~",
+	"cpe:/a:foo:bar:%",
+	"cpe:/a:foo:bar:%2",
+	"cpe:/a:foo:bar:1.0::~",
+	"cpe:/",
+}
+
+var fuzzFSs = []string{
+	"cpe:2.3:a:microsoft:internet_explorer:8.0.6001:beta:*:*:*:*:*:*",
+	`cpe:2.3:a:hp:insight_diagnostics:7.4.0.1570:-:*:*:online:win2003:x64:*`,
+	`cpe:2.3:a:foo\\bar:big\$money:2010:*:*:*:special:ipod_touch:80gb:*`,
+	`cpe:2.3:a:foo:bar:*:*:*:*:*:*:*:\`,
+	`cpe:2.3:a:foo:bar:?\:*:*:*:*:*:*:*`,
+	"cpe:2.3:",
+}
+
+func FuzzUnbindURI(f *testing.F) {
+	for _, s := range fuzzURIs {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		wfn, err := UnbindURI(s)
+		if err != nil {
+			return
+		}
+		uri := BindToURI(wfn)
+		again, err := UnbindURI(uri)
+		if err != nil {
+			t.Fatalf("UnbindURI(%q) = %v, re-bound to %q which fails: %v", s, wfn, uri, err)
+		}
+		if BindToURI(again) != uri {
+			t.Fatalf("URI round trip of %q: got %q, want %q", s, BindToURI(again), uri)
+		}
+	})
+}
+
+func FuzzUnbindFS(f *testing.F) {
+	for _, s := range fuzzFSs {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		wfn, err := UnbindFS(s)
+		if err != nil {
+			return
+		}
+		fs := BindToFS(wfn)
+		again, err := UnbindFS(fs)
+		if err != nil {
+			t.Fatalf("UnbindFS(%q) = %v, re-bound to %q which fails: %v", s, wfn, fs, err)
+		}
+		if BindToFS(again) != fs {
+			t.Fatalf("FS round trip of %q: got %q, want %q", s, BindToFS(again), fs)
+		}
+		fromText, err := UnbindWFN(BindToWFN(wfn))
+		if err != nil {
+			t.Fatalf("UnbindFS(%q) = %v which fails to unbind: %v", s, wfn, err)
+		}
+		if BindToFS(fromText) != fs {
+			t.Fatalf("WFN round trip of %q: got %q, want %q", s, BindToFS(fromText), fs)
+		}
+	})
+}
+
+func FuzzUnbindWFN(f *testing.F) {
+	f.Add(`wfn:[part="a", vendor="microsoft", product="internet_explorer", version="8\.0\.6001", update=ANY]`)
+	f.Add(`wfn:[ update = NA,vendor="foo\"bar" , part="o" ]`)
+	f.Add(`wfn:[part=ANY]`)
+	f.Fuzz(func(t *testing.T, s string) {
+		wfn, err := UnbindWFN(s)
+		if err != nil {
+			return
+		}
+		again, err := UnbindWFN(wfn.String())
+		if err != nil {
+			t.Fatalf("UnbindWFN(%q) = %v which fails to unbind: %v", s, wfn, err)
+		}
+		if again.String() != wfn.String() {
+			t.Fatalf("WFN round trip of %q: got %v, want %v", s, again, wfn)
+		}
+	})
+}
+
+func FuzzUnbindLenient(f *testing.F) {
+	for _, s := range append(fuzzURIs, fuzzFSs...) {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		UnbindURILenient(s, RepairAll)
+		UnbindFSLenient(s, RepairAll)
+	})
+}
+
+func FuzzBindRoundTrip(f *testing.F) {
+	f.Add("microsoft", "internet_explorer", `8\.0\.6001`, "beta")
+	f.Add(`foo\\bar`, `big\$money`, "*2010", "???")
+	f.Add(`\`, "a", `\-`, "")
+	f.Fuzz(func(t *testing.T, vendor, product, version, other string) {
+		// Binding must not panic even on values that fail validation.
+		raw := common.WellFormedName{
+			common.AttributePart:    "a",
+			common.AttributeVendor:  vendor,
+			common.AttributeProduct: product,
+			common.AttributeVersion: version,
+			common.AttributeOther:   other,
+		}
+		BindToURI(raw)
+		BindToFS(raw)
+
+		wfn := common.NewWellFormedName()
+		values := map[string]string{
+			common.AttributePart:    "a",
+			common.AttributeVendor:  vendor,
+			common.AttributeProduct: product,
+			common.AttributeVersion: version,
+			common.AttributeOther:   other,
+		}
+		for attr, v := range values {
+			// empty values and unquoted symbols are valid but are not
+			// kept by a round trip
+			if v == "" || hasUnquotedSymbol(v) || wfn.Set(attr, v) != nil {
+				return
+			}
+		}
+		fs := BindToFS(wfn)
+		fromFS, err := UnbindFS(fs)
+		if err != nil {
+			t.Fatalf("BindToFS(%v) = %q which fails to unbind: %v", wfn, fs, err)
+		}
+		if BindToFS(fromFS) != fs {
+			t.Fatalf("FS round trip of %v: got %q, want %q", wfn, BindToFS(fromFS), fs)
+		}
+		uri := BindToURI(wfn)
+		if _, err := UnbindURI(uri); err != nil {
+			t.Fatalf("BindToURI(%v) = %q which fails to unbind: %v", wfn, uri, err)
+		}
+	})
+}
+
+// hasUnquotedSymbol returns true if s contains a symbol such as + or $ not
+// quoted by a backslash
+func hasUnquotedSymbol(s string) bool {
+	quoted := false
+	for _, r := range s {
+		if !quoted && unicode.IsSymbol(r) {
+			return true
+		}
+		quoted = !quoted && r == '\\'
+	}
+	return false
+}
+
+func FuzzParsePackageURL(f *testing.F) {
+	f.Add("pkg:npm/%40angular/core@12.0.0")
+	f.Add("pkg:deb/debian/openssl@1.1.1n-0+deb11u3?arch=amd64&distro=debian-11#sub/path")
+	f.Add("pkg:maven/org.apache.tomcat/tomcat@")
+	f.Add("pkg:/%")
+	f.Fuzz(func(t *testing.T, s string) {
+		p, err := ParsePackageURL(s)
+		if err != nil {
+			return
+		}
+		PurlToWFNs(p, nil)
+		if _, err := ParsePackageURL(p.String()); err != nil {
+			t.Fatalf("ParsePackageURL(%q) = %q which fails to parse: %v", s, p.String(), err)
+		}
+	})
+}
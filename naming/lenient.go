@@ -0,0 +1,260 @@
+package naming
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/knqyf263/go-cpe/common"
+)
+
+// RepairKind is a set of repairs lenient unbinding may apply to a malformed name.
+// Kinds are combined with bitwise OR to select the repairs that are allowed.
+type RepairKind uint
+
+const (
+	// RepairWhitespace trims surrounding whitespace and replaces embedded
+	// whitespace (and %20 in URIs) with '_'
+	RepairWhitespace RepairKind = 1 << iota
+	// RepairPunctuation quotes punctuation that the binding requires to be
+	// quoted or percent-encoded, including embedded wildcards
+	RepairPunctuation
+	// RepairMissingComponents pads a formatted string with ANY up to eleven attributes
+	RepairMissingComponents
+	// RepairEmptyComponents replaces empty components of a formatted string with ANY
+	RepairEmptyComponents
+	// RepairCase converts a formatted string to lowercase
+	RepairCase
+	// RepairTrailingColons removes trailing colons
+	RepairTrailingColons
+
+	// RepairAll allows every repair
+	RepairAll = RepairWhitespace | RepairPunctuation | RepairMissingComponents |
+		RepairEmptyComponents | RepairCase | RepairTrailingColons
+)
+
+var repairKinds = map[RepairKind]string{
+	RepairWhitespace:        "WHITESPACE",
+	RepairPunctuation:       "PUNCTUATION",
+	RepairMissingComponents: "MISSING_COMPONENTS",
+	RepairEmptyComponents:   "EMPTY_COMPONENTS",
+	RepairCase:              "CASE",
+	RepairTrailingColons:    "TRAILING_COLONS",
+}
+
+// String returns string representation of a single RepairKind
+func (k RepairKind) String() string {
+	if s, ok := repairKinds[k]; ok {
+		return s
+	}
+	return "UNKNOWN"
+}
+
+// Repair describes a change lenient unbinding made to its input.
+type Repair struct {
+	Kind RepairKind
+	// Component is the index of the repaired component, numbered as in
+	// common.ParseError, or -1 if the whole name was repaired
+	Component int
+	Before    string
+	After     string
+}
+
+// String returns string representation of the Repair
+func (r Repair) String() string {
+	if r.Component < 0 {
+		return fmt.Sprintf("%s: %q -> %q", r.Kind, r.Before, r.After)
+	}
+	return fmt.Sprintf("%s (component %d): %q -> %q", r.Kind, r.Component, r.Before, r.After)
+}
+
+// UnbindFSLenient unbinds a formatted string to a WFN, first repairing the
+// common defects selected by allowed, e.g. cpe:2.3:a:Vendor:Product:1.0
+// is repaired to cpe:2.3:a:vendor:product:1.0:*:*:*:*:*:*:*.
+// With no repairs allowed it behaves as UnbindFS.
+// @param fs Formatted string to unbind
+// @param allowed repairs that may be applied
+// @return WellFormedName, and the repairs that were applied
+func UnbindFSLenient(fs string, allowed RepairKind) (common.WellFormedName, []Repair, error) {
+	r := repairer{allowed: allowed}
+	s := r.apply(RepairWhitespace, -1, fs, strings.TrimSpace)
+	s = r.apply(RepairCase, -1, s, strings.ToLower)
+
+	comps := splitFS(s)
+	s = r.apply(RepairTrailingColons, -1, s, func(s string) string {
+		n := len(comps)
+		for n > 13 && comps[n-1] == "" {
+			n--
+		}
+		comps = comps[:n]
+		return strings.Join(comps, ":")
+	})
+	if len(comps) >= 2 {
+		r.apply(RepairMissingComponents, -1, s, func(s string) string {
+			for len(comps) < 13 {
+				comps = append(comps, "*")
+			}
+			return strings.Join(comps, ":")
+		})
+	}
+	for i := 2; i < len(comps); i++ {
+		comps[i] = r.apply(RepairEmptyComponents, i, comps[i], func(s string) string {
+			if s == "" {
+				return "*"
+			}
+			return s
+		})
+		comps[i] = r.apply(RepairWhitespace, i, comps[i], replaceWhitespace)
+		comps[i] = r.apply(RepairPunctuation, i, comps[i], quoteFS)
+	}
+
+	wfn, err := UnbindFS(strings.Join(comps, ":"))
+	if err != nil {
+		return nil, r.repairs, err
+	}
+	return wfn, r.repairs, nil
+}
+
+// UnbindURILenient unbinds a URI to a WFN, first repairing the common defects
+// selected by allowed, e.g. cpe:/a:vendor:product name: is repaired to
+// cpe:/a:vendor:product_name.  URIs are case insensitive, so RepairCase does not apply.
+// With no repairs allowed it behaves as UnbindURI.
+// @param uri String representing the URI to unbind
+// @param allowed repairs that may be applied
+// @return WellFormedName, and the repairs that were applied
+func UnbindURILenient(uri string, allowed RepairKind) (common.WellFormedName, []Repair, error) {
+	r := repairer{allowed: allowed}
+	s := r.apply(RepairWhitespace, -1, uri, strings.TrimSpace)
+	s = r.apply(RepairTrailingColons, -1, s, func(s string) string {
+		return strings.TrimRight(s, ":")
+	})
+
+	comps := strings.Split(s, ":")
+	for i := 1; i < len(comps); i++ {
+		prefix := ""
+		if i == 1 {
+			v := strings.TrimLeft(comps[1], "/")
+			prefix, comps[1] = comps[1][:len(comps[1])-len(v)], v
+		}
+		comps[i] = r.apply(RepairWhitespace, i, comps[i], func(s string) string {
+			return replaceWhitespace(strings.Replace(s, "%20", " ", -1))
+		})
+		packed := i == 6 && strings.HasPrefix(comps[i], "~")
+		comps[i] = r.apply(RepairPunctuation, i, comps[i], func(s string) string {
+			return encodeURI(s, packed)
+		})
+		comps[i] = prefix + comps[i]
+	}
+
+	wfn, err := UnbindURI(strings.Join(comps, ":"))
+	if err != nil {
+		return nil, r.repairs, err
+	}
+	return wfn, r.repairs, nil
+}
+
+// repairer applies the allowed repairs and records the ones that changed something.
+type repairer struct {
+	allowed RepairKind
+	repairs []Repair
+}
+
+func (r *repairer) apply(kind RepairKind, component int, s string, fix func(string) string) string {
+	if r.allowed&kind == 0 {
+		return s
+	}
+	fixed := fix(s)
+	if fixed != s {
+		r.repairs = append(r.repairs, Repair{Kind: kind, Component: component, Before: s, After: fixed})
+	}
+	return fixed
+}
+
+// splitFS splits a formatted string at unescaped colons.
+func splitFS(fs string) []string {
+	var comps []string
+	start := 0
+	for i := 0; i < len(fs); i++ {
+		if fs[i] == '\\' {
+			i++
+			continue
+		}
+		if fs[i] == ':' {
+			comps = append(comps, fs[start:i])
+			start = i + 1
+		}
+	}
+	return append(comps, fs[start:])
+}
+
+// replaceWhitespace trims s and replaces each run of embedded whitespace with '_'.
+func replaceWhitespace(s string) string {
+	if strings.IndexFunc(s, unicode.IsSpace) == -1 {
+		return s
+	}
+	return strings.Join(strings.Fields(s), "_")
+}
+
+// quoteFS quotes embedded wildcards and a trailing backslash in a formatted string component.
+func quoteFS(s string) string {
+	if s == "*" || s == "-" {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s):
+			b.WriteString(s[i : i+2])
+			i++
+		case c == '\\':
+			b.WriteString(`\\`)
+		case c == '*' && i != 0 && i != len(s)-1:
+			b.WriteString(`\*`)
+		case c == '?' && strings.Trim(s[:i], "?") != "" && strings.Trim(s[i+1:], "?") != "":
+			b.WriteString(`\?`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// encodeURI percent-encodes punctuation and non-ASCII bytes in a URI component, and a '%' that
+// does not start a legal percent-encoded form.  The '~' delimiter of a packed
+// edition is kept.
+func encodeURI(s string, packed bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '%':
+			if i+3 <= len(s) && isPctForm(s[i:i+3]) {
+				b.WriteString(s[i : i+3])
+				i += 2
+			} else {
+				b.WriteString("%25")
+			}
+		case c == '~' && packed:
+			b.WriteByte(c)
+		case c >= utf8.RuneSelf:
+			fmt.Fprintf(&b, "%%%02x", c)
+		case c == '.' || c == '-' || c == '~' || common.IsAlphanum(string(c)):
+			b.WriteByte(c)
+		default:
+			b.WriteString(pctEncode(string(c)))
+		}
+	}
+	return b.String()
+}
+
+// isPctForm returns true if form is a percent-encoded form that decode accepts on its own.
+func isPctForm(form string) bool {
+	if b, ok := unhex(form); ok && b >= utf8.RuneSelf {
+		// part of a percent-encoded UTF-8 sequence, which decode validates as a whole
+		return true
+	}
+	_, err := decode(strings.ToLower(form))
+	return err == nil
+}
@@ -0,0 +1,161 @@
+package naming
+
+import (
+	"testing"
+
+	"github.com/knqyf263/go-cpe/common"
+	"github.com/pkg/errors"
+)
+
+func TestUnbindFSLenient(t *testing.T) {
+	vectors := []struct {
+		s        string
+		allowed  RepairKind
+		expected string
+		repairs  []RepairKind
+		wantErr  error
+	}{{
+		s:        "cpe:2.3:a:microsoft:internet_explorer:8.0.6001:beta:*:*:*:*:*:*",
+		allowed:  RepairAll,
+		expected: "cpe:2.3:a:microsoft:internet_explorer:8.0.6001:beta:*:*:*:*:*:*",
+	}, {
+		s:        "cpe:2.3:a:vendor:product:1.0",
+		allowed:  RepairAll,
+		expected: "cpe:2.3:a:vendor:product:1.0:*:*:*:*:*:*:*",
+		repairs:  []RepairKind{RepairMissingComponents},
+	}, {
+		s:        "  CPE:2.3:a:Microsoft:Internet Explorer:8.0:*:*:*:*:*:*:*\n",
+		allowed:  RepairAll,
+		expected: "cpe:2.3:a:microsoft:internet_explorer:8.0:*:*:*:*:*:*:*",
+		repairs:  []RepairKind{RepairWhitespace, RepairCase, RepairWhitespace},
+	}, {
+		s:        "cpe:2.3:a:foo:bar::*:*:*:*:*:*:*:::",
+		allowed:  RepairAll,
+		expected: "cpe:2.3:a:foo:bar:*:*:*:*:*:*:*:*",
+		repairs:  []RepairKind{RepairTrailingColons, RepairEmptyComponents},
+	}, {
+		s:        `cpe:2.3:a:foo*bar:b?r??:1.0:*:*:*:*:*:*:x\`,
+		allowed:  RepairAll,
+		expected: `cpe:2.3:a:foo\*bar:b\?r??:1.0:*:*:*:*:*:*:x\\`,
+		repairs:  []RepairKind{RepairPunctuation, RepairPunctuation, RepairPunctuation},
+	}, {
+		// the repair is not allowed
+		s:       "cpe:2.3:a:vendor:product:1.0",
+		allowed: RepairAll &^ RepairMissingComponents,
+		wantErr: common.ErrParse,
+	}, {
+		s:       "cpe:2.3:a:foo*bar:*:*:*:*:*:*:*:*:*",
+		wantErr: common.ErrParse,
+	}, {
+		// too many components cannot be repaired
+		s:       "cpe:2.3:a:foo:bar:*:*:*:*:*:*:*:*:baz",
+		allowed: RepairAll,
+		wantErr: common.ErrParse,
+	}, {
+		s:       "cpe:/a:foo:bar",
+		allowed: RepairAll,
+		wantErr: common.ErrParse,
+	},
+	}
+
+	for i, v := range vectors {
+		wfn, repairs, err := UnbindFSLenient(v.s, v.allowed)
+		if errors.Cause(err) != v.wantErr {
+			t.Errorf("test %d, Error: got %v, want %v", i, err, v.wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		if actual := BindToFS(wfn); actual != v.expected {
+			t.Errorf("test %d, Result: got %v, want %v", i, actual, v.expected)
+		}
+		if len(repairs) != len(v.repairs) {
+			t.Errorf("test %d, Repairs: got %v, want %v", i, repairs, v.repairs)
+			continue
+		}
+		for j, r := range repairs {
+			if r.Kind != v.repairs[j] {
+				t.Errorf("test %d, Repair %d: got %v, want %v", i, j, r.Kind, v.repairs[j])
+			}
+		}
+	}
+}
+
+func TestUnbindURILenient(t *testing.T) {
+	vectors := []struct {
+		s        string
+		allowed  RepairKind
+		expected string
+		repairs  []RepairKind
+		wantErr  error
+	}{{
+		s:        "cpe:/a:microsoft:internet_explorer:8.0.6001:beta",
+		allowed:  RepairAll,
+		expected: "cpe:/a:microsoft:internet_explorer:8.0.6001:beta",
+	}, {
+		s:        "cpe:/a:foo:bar:1.0:beta::en-us:",
+		allowed:  RepairAll,
+		expected: "cpe:/a:foo:bar:1.0:beta::en-us",
+		repairs:  []RepairKind{RepairTrailingColons},
+	}, {
+		s:        " cpe:/a:Microsoft:Internet%20Explorer 8:8.0 ",
+		allowed:  RepairAll,
+		expected: "cpe:/a:microsoft:internet_explorer_8:8.0",
+		repairs:  []RepairKind{RepairWhitespace, RepairWhitespace},
+	}, {
+		s:        "cpe:/a:at&t:c++:100%:1.0:~~online~win2003!~x64~",
+		allowed:  RepairAll,
+		expected: "cpe:/a:at%26t:c%2b%2b:100%25:1.0:~~online~win2003%21~x64~",
+		repairs:  []RepairKind{RepairPunctuation, RepairPunctuation, RepairPunctuation, RepairPunctuation},
+	}, {
+		s:       "cpe:/a:at&t",
+		allowed: RepairAll &^ RepairPunctuation,
+		wantErr: common.ErrParse,
+	}, {
+		s:       "cpe:/a:foo:bar:1.0:beta::en-us:",
+		wantErr: common.ErrParse,
+	},
+	}
+
+	for i, v := range vectors {
+		wfn, repairs, err := UnbindURILenient(v.s, v.allowed)
+		if errors.Cause(err) != v.wantErr {
+			t.Errorf("test %d, Error: got %v, want %v", i, err, v.wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		if actual := BindToURI(wfn); actual != v.expected {
+			t.Errorf("test %d, Result: got %v, want %v", i, actual, v.expected)
+		}
+		if len(repairs) != len(v.repairs) {
+			t.Errorf("test %d, Repairs: got %v, want %v", i, repairs, v.repairs)
+			continue
+		}
+		for j, r := range repairs {
+			if r.Kind != v.repairs[j] {
+				t.Errorf("test %d, Repair %d: got %v, want %v", i, j, r.Kind, v.repairs[j])
+			}
+		}
+	}
+}
+
+func TestRepairString(t *testing.T) {
+	vectors := []struct {
+		r        Repair
+		expected string
+	}{{
+		r:        Repair{Kind: RepairCase, Component: -1, Before: "CPE:2.3:A", After: "cpe:2.3:a"},
+		expected: `CASE: "CPE:2.3:A" -> "cpe:2.3:a"`,
+	}, {
+		r:        Repair{Kind: RepairWhitespace, Component: 3, Before: "foo bar", After: "foo_bar"},
+		expected: `WHITESPACE (component 3): "foo bar" -> "foo_bar"`,
+	},
+	}
+
+	for i, v := range vectors {
+		if actual := v.r.String(); actual != v.expected {
+			t.Errorf("test %d, Result: got %v, want %v", i, actual, v.expected)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package naming
+
+import (
+	"strings"
+
+	"github.com/knqyf263/go-cpe/common"
+)
+
+// Binding is enumeration for the forms a CPE name can be written in.
+type Binding int
+
+const (
+	// BindingUnknown : not a recognized binding
+	BindingUnknown Binding = iota
+	// BindingURI : CPE 2.2 URI, e.g. cpe:/a:microsoft:internet_explorer:8.0.6001
+	BindingURI
+	// BindingFS : CPE 2.3 formatted string, e.g. cpe:2.3:a:microsoft:internet_explorer:8.0.6001:*:*:*:*:*:*:*
+	BindingFS
+	// BindingWFN : text form of a WFN, e.g. wfn:[part="a", vendor="microsoft"]
+	BindingWFN
+)
+
+// String returns string representation of the Binding
+func (b Binding) String() string {
+	switch b {
+	case BindingURI:
+		return "URI"
+	case BindingFS:
+		return "FS"
+	case BindingWFN:
+		return "WFN"
+	}
+	return "UNKNOWN"
+}
+
+// DetectBinding returns the binding of s from its prefix, without validating the rest of s.
+// @param s CPE name
+// @return Binding, or BindingUnknown
+func DetectBinding(s string) Binding {
+	prefix := strings.ToLower(s)
+	switch {
+	case strings.HasPrefix(prefix, "cpe:2.3:"):
+		return BindingFS
+	case strings.HasPrefix(prefix, "cpe:/"):
+		return BindingURI
+	case strings.HasPrefix(prefix, "wfn:["):
+		return BindingWFN
+	}
+	return BindingUnknown
+}
+
+// Parse unbinds a URI, a formatted string or the text form of a WFN,
+// detecting the binding from its prefix.
+// @param s CPE name
+// @return WellFormedName, and the detected binding
+func Parse(s string) (common.WellFormedName, Binding, error) {
+	var wfn common.WellFormedName
+	var err error
+	b := DetectBinding(s)
+	switch b {
+	case BindingURI:
+		wfn, err = UnbindURI(s)
+	case BindingFS:
+		wfn, err = UnbindFS(s)
+	case BindingWFN:
+		wfn, err = UnbindWFN(s)
+	default:
+		perr := common.NewParseError(s, common.ReasonBadPrefix, 0, "Error: CPE name must start with 'cpe:/', 'cpe:2.3:' or 'wfn:['")
+		perr.Component = 0
+		return nil, BindingUnknown, perr
+	}
+	if err != nil {
+		return nil, b, err
+	}
+	return wfn, b, nil
+}
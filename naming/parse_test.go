@@ -0,0 +1,66 @@
+package naming
+
+import (
+	"testing"
+
+	"github.com/knqyf263/go-cpe/common"
+	"github.com/pkg/errors"
+)
+
+func TestParse(t *testing.T) {
+	vectors := []struct {
+		s        string
+		binding  Binding
+		expected string
+		wantErr  error
+	}{{
+		s:        "cpe:/a:microsoft:internet_explorer:8.0.6001:beta",
+		binding:  BindingURI,
+		expected: "cpe:2.3:a:microsoft:internet_explorer:8.0.6001:beta:*:*:*:*:*:*",
+	}, {
+		s:        "CPE:/a:microsoft:internet_explorer",
+		binding:  BindingURI,
+		expected: "cpe:2.3:a:microsoft:internet_explorer:*:*:*:*:*:*:*:*",
+	}, {
+		s:        "cpe:2.3:a:microsoft:internet_explorer:8.0.6001:beta:*:*:*:*:*:*",
+		binding:  BindingFS,
+		expected: "cpe:2.3:a:microsoft:internet_explorer:8.0.6001:beta:*:*:*:*:*:*",
+	}, {
+		s:        `wfn:[part="a", vendor="microsoft", product="internet_explorer", version="8\.0\.6001", update="beta"]`,
+		binding:  BindingWFN,
+		expected: "cpe:2.3:a:microsoft:internet_explorer:8.0.6001:beta:*:*:*:*:*:*",
+	}, {
+		s:       "cpe:2.3:a:microsoft",
+		binding: BindingFS,
+		wantErr: common.ErrParse,
+	}, {
+		s:       `wfn:[part="a"`,
+		binding: BindingWFN,
+		wantErr: common.ErrParse,
+	}, {
+		s:       "pkg:npm/foo@1.0.0",
+		binding: BindingUnknown,
+		wantErr: common.ErrParse,
+	}, {
+		s:       "",
+		binding: BindingUnknown,
+		wantErr: common.ErrParse,
+	},
+	}
+
+	for i, v := range vectors {
+		wfn, binding, err := Parse(v.s)
+		if errors.Cause(err) != v.wantErr {
+			t.Errorf("test %d, Error: got %v, want %v", i, err, v.wantErr)
+		}
+		if binding != v.binding {
+			t.Errorf("test %d, Binding: got %v, want %v", i, binding, v.binding)
+		}
+		if err != nil {
+			continue
+		}
+		if actual := BindToFS(wfn); actual != v.expected {
+			t.Errorf("test %d, Result: got %v, want %v", i, actual, v.expected)
+		}
+	}
+}
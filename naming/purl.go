@@ -0,0 +1,177 @@
+package naming
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/knqyf263/go-cpe/common"
+	"github.com/pkg/errors"
+)
+
+// PackageURL represents a package URL (purl), as defined
+// in the package-url specification.
+// @see <a href="https://github.com/package-url/purl-spec">purl-spec</a> for details.
+type PackageURL struct {
+	Type       string
+	Namespace  string
+	Name       string
+	Version    string
+	Qualifiers map[string]string
+	Subpath    string
+}
+
+// ParsePackageURL parses a package URL of the form
+// pkg:type/namespace/name@version?qualifiers#subpath.
+// @param s String representing the package URL
+// @return PackageURL
+func ParsePackageURL(s string) (PackageURL, error) {
+	var p PackageURL
+	rest := strings.TrimSpace(s)
+	if !strings.HasPrefix(strings.ToLower(rest), "pkg:") {
+		return PackageURL{}, errors.Wrapf(common.ErrParse, "package URL must start with 'pkg:': %s", s)
+	}
+	rest = strings.TrimLeft(rest[4:], "/")
+
+	if i := strings.LastIndex(rest, "#"); i >= 0 {
+		subpath, err := unescapeSegments(strings.Trim(rest[i+1:], "/"))
+		if err != nil {
+			return PackageURL{}, errors.Wrapf(common.ErrParse, "invalid subpath: %s", s)
+		}
+		p.Subpath = subpath
+		rest = rest[:i]
+	}
+	if i := strings.LastIndex(rest, "?"); i >= 0 {
+		p.Qualifiers = map[string]string{}
+		for _, kv := range strings.Split(rest[i+1:], "&") {
+			if kv == "" {
+				continue
+			}
+			eq := strings.Index(kv, "=")
+			if eq <= 0 {
+				return PackageURL{}, errors.Wrapf(common.ErrParse, "invalid qualifier %s: %s", kv, s)
+			}
+			v, err := url.PathUnescape(kv[eq+1:])
+			if err != nil {
+				return PackageURL{}, errors.Wrapf(common.ErrParse, "invalid qualifier %s: %s", kv, s)
+			}
+			if v != "" {
+				p.Qualifiers[strings.ToLower(kv[:eq])] = v
+			}
+		}
+		rest = rest[:i]
+	}
+
+	rest = strings.TrimRight(rest, "/")
+	slash := strings.Index(rest, "/")
+	if slash <= 0 {
+		return PackageURL{}, errors.Wrapf(common.ErrParse, "package URL must have a type and a name: %s", s)
+	}
+	p.Type = strings.ToLower(rest[:slash])
+	if !isPurlType(p.Type) {
+		return PackageURL{}, errors.Wrapf(common.ErrParse, "invalid type %s: %s", p.Type, s)
+	}
+	rest = rest[slash+1:]
+
+	// the version separator is the last '@' of the name segment,
+	// npm scopes may start the namespace with an unencoded '@'.
+	if i := strings.LastIndex(rest, "@"); i > strings.LastIndex(rest, "/") {
+		v, err := url.PathUnescape(rest[i+1:])
+		if err != nil {
+			return PackageURL{}, errors.Wrapf(common.ErrParse, "invalid version: %s", s)
+		}
+		p.Version = v
+		rest = rest[:i]
+	}
+
+	name := rest
+	if i := strings.LastIndex(rest, "/"); i >= 0 {
+		namespace, err := unescapeSegments(strings.Trim(rest[:i], "/"))
+		if err != nil {
+			return PackageURL{}, errors.Wrapf(common.ErrParse, "invalid namespace: %s", s)
+		}
+		p.Namespace = namespace
+		name = rest[i+1:]
+	}
+	n, err := url.PathUnescape(name)
+	if err != nil || n == "" {
+		return PackageURL{}, errors.Wrapf(common.ErrParse, "invalid name: %s", s)
+	}
+	p.Name = n
+	return p, nil
+}
+
+// String returns the canonical string representation of the PackageURL
+func (p PackageURL) String() string {
+	s := "pkg:" + strings.ToLower(p.Type) + "/"
+	if p.Namespace != "" {
+		s += escapeSegments(p.Namespace) + "/"
+	}
+	s += escape(p.Name)
+	if p.Version != "" {
+		s += "@" + escape(p.Version)
+	}
+	if len(p.Qualifiers) > 0 {
+		var keys []string
+		for k, v := range p.Qualifiers {
+			if v != "" {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		var qs []string
+		for _, k := range keys {
+			qs = append(qs, strings.ToLower(k)+"="+escape(p.Qualifiers[k]))
+		}
+		if len(qs) > 0 {
+			s += "?" + strings.Join(qs, "&")
+		}
+	}
+	if p.Subpath != "" {
+		s += "#" + escapeSegments(p.Subpath)
+	}
+	return s
+}
+
+// isPurlType returns true if s is composed only of ASCII letters, digits, '.', '+'
+// and '-', and does not start with a digit.
+func isPurlType(s string) bool {
+	for i, r := range s {
+		switch {
+		case common.IsAlpha(r), r == '.', r == '+', r == '-':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func unescapeSegments(s string) (string, error) {
+	var segments []string
+	for _, seg := range strings.Split(s, "/") {
+		if seg == "" {
+			continue
+		}
+		u, err := url.PathUnescape(seg)
+		if err != nil {
+			return "", err
+		}
+		segments = append(segments, u)
+	}
+	return strings.Join(segments, "/"), nil
+}
+
+func escapeSegments(s string) string {
+	segments := strings.Split(s, "/")
+	for i, seg := range segments {
+		segments[i] = escape(seg)
+	}
+	return strings.Join(segments, "/")
+}
+
+// escape percent-encodes a purl component, including '@'
+// which url.PathEscape leaves as is.
+func escape(s string) string {
+	return strings.Replace(url.PathEscape(s), "@", "%40", -1)
+}
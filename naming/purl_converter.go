@@ -0,0 +1,213 @@
+package naming
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/knqyf263/go-cpe/common"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrNoPackageURL is returned when a WFN cannot be converted to a package URL
+	ErrNoPackageURL = errors.New("No package URL")
+
+	// targetSwTypes maps CPE target_sw values to purl types whose namespace
+	// is empty, or is the vendor for composer
+	targetSwTypes = map[string]string{
+		"node.js": "npm",
+		"python":  "pypi",
+		"ruby":    "gem",
+		"rails":   "gem",
+		"rust":    "cargo",
+		"php":     "composer",
+		".net":    "nuget",
+		"perl":    "cpan",
+	}
+
+	// purlTypeTargetSws maps purl types back to CPE target_sw values, so that
+	// WFNToPurl gives the type again
+	purlTypeTargetSws = map[string]string{
+		"npm":      "node.js",
+		"pypi":     "python",
+		"gem":      "ruby",
+		"cargo":    "rust",
+		"composer": "php",
+		"nuget":    ".net",
+		"cpan":     "perl",
+	}
+)
+
+// PurlMappingEntry maps a package to a CPE vendor and product,
+// for ecosystems where they differ from the purl namespace and name.
+// Values are unquoted and compared case insensitively; an empty Namespace
+// only matches packages without a namespace.
+type PurlMappingEntry struct {
+	Type      string
+	Namespace string
+	Name      string
+	Vendor    string
+	Product   string
+	// TargetSw is the optional target_sw of the CPE, e.g. "node.js"
+	TargetSw string
+}
+
+// PurlMapping is a table of PurlMappingEntry consulted before the default heuristics.
+type PurlMapping []PurlMappingEntry
+
+func (m PurlMapping) lookupPurl(p PackageURL) []PurlMappingEntry {
+	var result []PurlMappingEntry
+	for _, e := range m {
+		if strings.EqualFold(e.Type, p.Type) && strings.EqualFold(e.Namespace, p.Namespace) &&
+			strings.EqualFold(e.Name, p.Name) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+func (m PurlMapping) lookupCPE(vendor, product string) (PurlMappingEntry, bool) {
+	for _, e := range m {
+		if strings.EqualFold(e.Vendor, vendor) && strings.EqualFold(e.Product, product) {
+			return e, true
+		}
+	}
+	return PurlMappingEntry{}, false
+}
+
+// PurlToWFNs produces candidate WFNs for a package URL.
+// The mapping is consulted first; if no entry matches, candidates are
+// derived from the namespace and name: the product is the name and the vendor
+// is the last namespace segment, the organization of a reverse-DNS namespace
+// (org.apache.commons gives apache), or the name itself.
+// The version is set from the purl version, target_hw from the "arch" qualifier,
+// and target_sw from the mapping entry.  Without a mapping entry, a candidate
+// with the target_sw of the purl type, e.g. node.js for npm, is followed by the
+// same candidate with target_sw ANY, as dictionary entries mostly have.
+// @param p PackageURL to convert
+// @param mapping PurlMapping, may be nil
+// @return candidate WFNs, most likely first
+func PurlToWFNs(p PackageURL, mapping PurlMapping) ([]common.WellFormedName, error) {
+	var entries []PurlMappingEntry
+	if entries = mapping.lookupPurl(p); len(entries) == 0 {
+		targetSw := purlTypeTargetSws[strings.ToLower(p.Type)]
+		for _, vendor := range vendorCandidates(p) {
+			entries = append(entries, PurlMappingEntry{Vendor: vendor, Product: p.Name, TargetSw: targetSw})
+			if targetSw != "" {
+				entries = append(entries, PurlMappingEntry{Vendor: vendor, Product: p.Name})
+			}
+		}
+	}
+
+	var result []common.WellFormedName
+	seen := map[string]bool{}
+	for _, e := range entries {
+		wfn := common.NewWellFormedName()
+		values := []struct {
+			attribute string
+			value     string
+		}{
+			{common.AttributePart, "a"},
+			{common.AttributeVendor, e.Vendor},
+			{common.AttributeProduct, e.Product},
+			{common.AttributeVersion, p.Version},
+			{common.AttributeTargetSw, e.TargetSw},
+			{common.AttributeTargetHw, p.Qualifiers["arch"]},
+		}
+		for _, v := range values {
+			if v.value == "" {
+				continue
+			}
+			if err := wfn.Set(v.attribute, quoteValue(v.value)); err != nil {
+				return nil, errors.Wrapf(err, "Failed to convert %s", p)
+			}
+		}
+		if fs := BindToFS(wfn); !seen[fs] {
+			seen[fs] = true
+			result = append(result, wfn)
+		}
+	}
+	return result, nil
+}
+
+// vendorCandidates derives vendor candidates from the namespace and name.
+func vendorCandidates(p PackageURL) []string {
+	var result []string
+	if p.Namespace != "" {
+		segments := strings.Split(p.Namespace, "/")
+		last := strings.TrimPrefix(segments[len(segments)-1], "@")
+		if labels := strings.Split(last, "."); len(labels) >= 2 && (p.Type == "maven" || len(labels) >= 3) {
+			// reverse-DNS namespace, the second label is the organization
+			result = append(result, labels[1])
+		}
+		result = append(result, last)
+	}
+	return append(result, p.Name)
+}
+
+// WFNToPurl converts a WFN to a package URL on a best-effort basis.
+// The mapping is consulted first; otherwise the purl type is derived from
+// target_sw, e.g. node.js gives npm, with the vendor as namespace for composer,
+// and falls back to "generic" with the vendor as namespace. Versions and target_hw that are not literals are omitted.
+// @param wfn WellFormedName to convert
+// @param mapping PurlMapping, may be nil
+// @return PackageURL, or ErrNoPackageURL if vendor or product are not literals
+func WFNToPurl(wfn common.WellFormedName, mapping PurlMapping) (PackageURL, error) {
+	vendor, ok := literal(wfn, common.AttributeVendor)
+	if !ok {
+		return PackageURL{}, errors.Wrapf(ErrNoPackageURL, "vendor is not a literal: %v", wfn.Get(common.AttributeVendor))
+	}
+	product, ok := literal(wfn, common.AttributeProduct)
+	if !ok {
+		return PackageURL{}, errors.Wrapf(ErrNoPackageURL, "product is not a literal: %v", wfn.Get(common.AttributeProduct))
+	}
+
+	var p PackageURL
+	if e, ok := mapping.lookupCPE(vendor, product); ok {
+		p = PackageURL{Type: e.Type, Namespace: e.Namespace, Name: e.Name}
+	} else if targetSw, ok := literal(wfn, common.AttributeTargetSw); ok && targetSwTypes[targetSw] != "" {
+		p = PackageURL{Type: targetSwTypes[targetSw], Name: product}
+		if p.Type == "composer" {
+			p.Namespace = vendor
+		}
+	} else {
+		p = PackageURL{Type: "generic", Namespace: vendor, Name: product}
+	}
+	if version, ok := literal(wfn, common.AttributeVersion); ok {
+		p.Version = version
+	}
+	if arch, ok := literal(wfn, common.AttributeTargetHw); ok {
+		p.Qualifiers = map[string]string{"arch": arch}
+	}
+	return p, nil
+}
+
+// literal returns the unquoted value of an attribute if it is a string without wildcards.
+func literal(wfn common.WellFormedName, attribute string) (string, bool) {
+	s, ok := wfn.Get(attribute).(string)
+	if !ok || common.ContainsWildcards(s) {
+		return "", false
+	}
+	return common.Unquote(s), true
+}
+
+// quoteValue converts an arbitrary string to a WFN attribute value:
+// letters are lowercased, whitespace and non-printable characters become
+// underscores, and all other non-alphanumerics, including non-ASCII
+// characters, are quoted.
+func quoteValue(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case common.IsAlphanum(string(r)):
+			b.WriteRune(r)
+		case r != utf8.RuneError && unicode.IsPrint(r) && !unicode.IsSpace(r):
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
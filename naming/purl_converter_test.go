@@ -0,0 +1,162 @@
+package naming
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestPurlToWFNs(t *testing.T) {
+	mapping := PurlMapping{{
+		Type:     "npm",
+		Name:     "lodash",
+		Vendor:   "lodash",
+		Product:  "lodash",
+		TargetSw: "node.js",
+	}}
+
+	vectors := []struct {
+		purl     string
+		expected []string
+	}{{
+		purl: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1",
+		expected: []string{
+			"cpe:2.3:a:apache:log4j-core:2.14.1:*:*:*:*:*:*:*",
+			"cpe:2.3:a:org.apache.logging.log4j:log4j-core:2.14.1:*:*:*:*:*:*:*",
+			"cpe:2.3:a:log4j-core:log4j-core:2.14.1:*:*:*:*:*:*:*",
+		},
+	}, {
+		purl:     "pkg:npm/lodash@4.17.21",
+		expected: []string{"cpe:2.3:a:lodash:lodash:4.17.21:*:*:*:*:node.js:*:*"},
+	}, {
+		purl: "pkg:pypi/django",
+		expected: []string{
+			"cpe:2.3:a:django:django:*:*:*:*:*:python:*:*",
+			"cpe:2.3:a:django:django:*:*:*:*:*:*:*:*",
+		},
+	}, {
+		purl: "pkg:deb/debian/curl@7.50.3-1?arch=i386",
+		expected: []string{
+			"cpe:2.3:a:debian:curl:7.50.3-1:*:*:*:*:*:i386:*",
+			"cpe:2.3:a:curl:curl:7.50.3-1:*:*:*:*:*:i386:*",
+		},
+	}, {
+		purl:     "pkg:generic/my%20app@1.0%2Bbuild",
+		expected: []string{"cpe:2.3:a:my_app:my_app:1.0\\+build:*:*:*:*:*:*:*"},
+	},
+	}
+
+	for i, v := range vectors {
+		p, err := ParsePackageURL(v.purl)
+		if err != nil {
+			t.Fatalf("test %d, Unexpected error: %s", i, err)
+		}
+		wfns, err := PurlToWFNs(p, mapping)
+		if err != nil {
+			t.Errorf("test %d, Unexpected error: %s", i, err)
+			continue
+		}
+		var actual []string
+		for _, wfn := range wfns {
+			actual = append(actual, BindToFS(wfn))
+		}
+		if !reflect.DeepEqual(actual, v.expected) {
+			t.Errorf("test %d, Result: %v, want %v", i, actual, v.expected)
+		}
+	}
+}
+
+func TestPurlRoundTrip(t *testing.T) {
+	vectors := []struct {
+		purl     string
+		expected string
+	}{
+		{purl: "pkg:npm/lodash@4.17.21", expected: "pkg:npm/lodash@4.17.21"},
+		{purl: "pkg:pypi/django@3.2", expected: "pkg:pypi/django@3.2"},
+		{purl: "pkg:gem/rails@6.1.0", expected: "pkg:gem/rails@6.1.0"},
+		{purl: "pkg:cargo/serde@1.0.0", expected: "pkg:cargo/serde@1.0.0"},
+		{purl: "pkg:nuget/newtonsoft.json@13.0.1", expected: "pkg:nuget/newtonsoft.json@13.0.1"},
+		{purl: "pkg:cpan/dbi@1.643", expected: "pkg:cpan/dbi@1.643"},
+		{purl: "pkg:composer/laravel/framework@8.0.0", expected: "pkg:composer/laravel/framework@8.0.0"},
+		{purl: "pkg:generic/curl/curl@7.50.3?arch=x86", expected: "pkg:generic/curl/curl@7.50.3?arch=x86"},
+		// a WFN does not hold the namespace of these types, so they come back generic
+		{purl: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1", expected: "pkg:generic/apache/log4j-core@2.14.1"},
+		{purl: "pkg:golang/github.com/gin-gonic/gin@1.7.0", expected: "pkg:generic/gin-gonic/gin@1.7.0"},
+	}
+
+	for i, v := range vectors {
+		p, err := ParsePackageURL(v.purl)
+		if err != nil {
+			t.Fatalf("test %d, Unexpected error: %s", i, err)
+		}
+		wfns, err := PurlToWFNs(p, nil)
+		if err != nil {
+			t.Errorf("test %d, Unexpected error: %s", i, err)
+			continue
+		}
+		actual, err := WFNToPurl(wfns[0], nil)
+		if err != nil {
+			t.Errorf("test %d, Unexpected error: %s", i, err)
+			continue
+		}
+		if actual.String() != v.expected {
+			t.Errorf("test %d, Result: %s, want %s", i, actual.String(), v.expected)
+		}
+	}
+}
+
+func TestWFNToPurl(t *testing.T) {
+	mapping := PurlMapping{{
+		Type:      "maven",
+		Namespace: "org.apache.logging.log4j",
+		Name:      "log4j-core",
+		Vendor:    "apache",
+		Product:   "log4j",
+	}}
+
+	vectors := []struct {
+		fs       string
+		expected string
+		wantErr  error
+	}{{
+		fs:       "cpe:2.3:a:apache:log4j:2.14.1:*:*:*:*:*:*:*",
+		expected: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1",
+	}, {
+		fs:       "cpe:2.3:a:lodash:lodash:4.17.21:*:*:*:*:node.js:*:*",
+		expected: "pkg:npm/lodash@4.17.21",
+	}, {
+		fs:       "cpe:2.3:a:laravel:framework:8.0.0:*:*:*:*:php:*:*",
+		expected: "pkg:composer/laravel/framework@8.0.0",
+	}, {
+		fs:       "cpe:2.3:a:apache:struts:2.5.0:*:*:*:*:maven:*:*",
+		expected: "pkg:generic/apache/struts@2.5.0",
+	}, {
+		fs:       "cpe:2.3:a:haxx:curl:7.50.*:*:*:*:*:*:x86:*",
+		expected: "pkg:generic/haxx/curl?arch=x86",
+	}, {
+		fs:      "cpe:2.3:a:*:curl:7.50.3:*:*:*:*:*:*:*",
+		wantErr: ErrNoPackageURL,
+	}, {
+		fs:      "cpe:2.3:a:haxx:-:7.50.3:*:*:*:*:*:*:*",
+		wantErr: ErrNoPackageURL,
+	},
+	}
+
+	for i, v := range vectors {
+		wfn, err := UnbindFS(v.fs)
+		if err != nil {
+			t.Fatalf("test %d, Unexpected error: %s", i, err)
+		}
+		actual, err := WFNToPurl(wfn, mapping)
+		if errors.Cause(err) != v.wantErr {
+			t.Errorf("test %d, Error: got %v, want %v", i, errors.Cause(err), v.wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		if actual.String() != v.expected {
+			t.Errorf("test %d, Result: %s, want %s", i, actual.String(), v.expected)
+		}
+	}
+}
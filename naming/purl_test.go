@@ -0,0 +1,101 @@
+package naming
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/knqyf263/go-cpe/common"
+	"github.com/pkg/errors"
+)
+
+func TestParsePackageURL(t *testing.T) {
+	vectors := []struct {
+		s        string
+		expected PackageURL
+		str      string
+		wantErr  error
+	}{{
+		s: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1",
+		expected: PackageURL{
+			Type:      "maven",
+			Namespace: "org.apache.logging.log4j",
+			Name:      "log4j-core",
+			Version:   "2.14.1",
+		},
+		str: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1",
+	}, {
+		s: "pkg:npm/%40angular/core@12.0.0",
+		expected: PackageURL{
+			Type:      "npm",
+			Namespace: "@angular",
+			Name:      "core",
+			Version:   "12.0.0",
+		},
+		str: "pkg:npm/%40angular/core@12.0.0",
+	}, {
+		s: "pkg:npm/@angular/core",
+		expected: PackageURL{
+			Type:      "npm",
+			Namespace: "@angular",
+			Name:      "core",
+		},
+		str: "pkg:npm/%40angular/core",
+	}, {
+		s: "pkg:deb/debian/curl@7.50.3-1?distro=jessie&arch=i386",
+		expected: PackageURL{
+			Type:       "deb",
+			Namespace:  "debian",
+			Name:       "curl",
+			Version:    "7.50.3-1",
+			Qualifiers: map[string]string{"arch": "i386", "distro": "jessie"},
+		},
+		str: "pkg:deb/debian/curl@7.50.3-1?arch=i386&distro=jessie",
+	}, {
+		s: "pkg:golang/github.com/gorilla/context@234fd47e07d1004f0aed9c#api/v1",
+		expected: PackageURL{
+			Type:      "golang",
+			Namespace: "github.com/gorilla",
+			Name:      "context",
+			Version:   "234fd47e07d1004f0aed9c",
+			Subpath:   "api/v1",
+		},
+		str: "pkg:golang/github.com/gorilla/context@234fd47e07d1004f0aed9c#api/v1",
+	}, {
+		s: "PKG:PyPI/django@1.11.1",
+		expected: PackageURL{
+			Type:    "pypi",
+			Name:    "django",
+			Version: "1.11.1",
+		},
+		str: "pkg:pypi/django@1.11.1",
+	}, {
+		s:       "npm/lodash@4.17.21",
+		wantErr: common.ErrParse,
+	}, {
+		s:       "pkg:lodash",
+		wantErr: common.ErrParse,
+	}, {
+		s:       "pkg:npm/lodash?arch",
+		wantErr: common.ErrParse,
+	}, {
+		s:       "pkg:npm/%zz",
+		wantErr: common.ErrParse,
+	},
+	}
+
+	for i, v := range vectors {
+		actual, err := ParsePackageURL(v.s)
+		if errors.Cause(err) != v.wantErr {
+			t.Errorf("test %d, Error: got %v, want %v", i, errors.Cause(err), v.wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		if !reflect.DeepEqual(actual, v.expected) {
+			t.Errorf("test %d, Result: %#v, want %#v", i, actual, v.expected)
+		}
+		if actual.String() != v.str {
+			t.Errorf("test %d, String: %s, want %s", i, actual.String(), v.str)
+		}
+	}
+}
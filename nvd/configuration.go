@@ -0,0 +1,220 @@
+// Package nvd decodes and evaluates the vulnerability configurations of
+// the NVD CVE JSON 1.1 feeds and the NVD CVE API 2.0.
+package nvd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/knqyf263/go-cpe/common"
+	"github.com/knqyf263/go-cpe/matching"
+	"github.com/knqyf263/go-cpe/naming"
+	"github.com/pkg/errors"
+)
+
+// Operator is the logical operator of a Node.
+type Operator string
+
+const (
+	// AND : every child must match
+	AND Operator = "AND"
+	// OR : at least one child must match
+	OR Operator = "OR"
+)
+
+// Node is an evaluable configuration node. Both JSON formats decode to Nodes.
+type Node struct {
+	Operator Operator
+	Negate   bool
+	Children []Node
+	Matches  []Match
+}
+
+// Match is a single CPE match criteria with optional version bounds.
+type Match struct {
+	Vulnerable bool
+	// Criteria is the formatted string of the match (cpe23Uri in 1.1, criteria in 2.0)
+	Criteria string
+	// WFN is Criteria unbound by naming.UnbindFS
+	WFN   common.WellFormedName
+	Range matching.VersionRange
+	// MatchCriteriaID is only set by the 2.0 API
+	MatchCriteriaID string
+}
+
+// Hit is a vulnerable match satisfied by a known platform.
+type Hit struct {
+	Match    Match
+	Platform common.WellFormedName
+}
+
+// Result is the verdict of evaluating configurations.
+type Result struct {
+	Vulnerable bool
+	// Hits are the vulnerable matches that caused the verdict
+	Hits []Hit
+	// Errors holds an error for each match and platform whose versions could
+	// not be compared
+	Errors []error
+}
+
+// ParseConfigurations decodes configurations in either format:
+// the "configurations" object of a CVE JSON 1.1 item, or the
+// "configurations" array of a CVE API 2.0 vulnerability.
+// @param data JSON configurations
+// @return root Nodes, any of which makes the CVE applicable
+func ParseConfigurations(data []byte) ([]Node, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var c []Configuration20
+		if err := json.Unmarshal(data, &c); err != nil {
+			return nil, errors.Wrap(err, "Failed to decode configurations")
+		}
+		return Configurations20(c).Convert()
+	}
+	var c Configurations11
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, errors.Wrap(err, "Failed to decode configurations")
+	}
+	return c.Convert()
+}
+
+// Evaluate evaluates configurations against the known platforms.
+// The result is vulnerable if a root node matches and at least one of its
+// vulnerable matches is satisfied; matches under a negated node never cause
+// a vulnerable verdict.  A version that cannot be compared with the bounds
+// of a match, e.g. one the comparator cannot parse, does not stop the
+// evaluation: the error is reported in the Errors of the result, and unless
+// the match applies to another platform it is unknown whether it applies.
+// An unknown match makes a node unknown unless the operator decides without
+// it, also under negation, and an unknown root node is not vulnerable.
+// @param nodes root Nodes
+// @param known WFNs of the platforms known to be present
+// @param cmp version comparator for version bounds, or nil for matching.DefaultVersionComparator
+// @return Result
+func Evaluate(nodes []Node, known []common.WellFormedName, cmp matching.VersionComparator) *Result {
+	result := &Result{}
+	for _, n := range nodes {
+		v, hits := n.evaluate(known, cmp, &result.Errors)
+		if v == yes && len(hits) > 0 {
+			result.Vulnerable = true
+			result.Hits = append(result.Hits, hits...)
+		}
+	}
+	return result
+}
+
+// Evaluate evaluates the node against the known platforms, as Evaluate does for root nodes.
+func (n Node) Evaluate(known []common.WellFormedName, cmp matching.VersionComparator) *Result {
+	return Evaluate([]Node{n}, known, cmp)
+}
+
+// verdict is whether a node or match applies, in the order AND and OR take
+// the minimum and maximum of.
+type verdict int
+
+const (
+	no verdict = iota
+	unknown
+	yes
+)
+
+func (n Node) evaluate(known []common.WellFormedName, cmp matching.VersionComparator, errs *[]error) (verdict, []Hit) {
+	var results []verdict
+	var hits []Hit
+	for _, m := range n.Matches {
+		failed := len(*errs)
+		platforms := m.matchedPlatforms(known, cmp, errs)
+		switch {
+		case len(platforms) > 0:
+			results = append(results, yes)
+		case len(*errs) > failed:
+			results = append(results, unknown)
+		default:
+			results = append(results, no)
+		}
+		if m.Vulnerable {
+			for _, p := range platforms {
+				hits = append(hits, Hit{Match: m, Platform: p})
+			}
+		}
+	}
+	for _, child := range n.Children {
+		v, childHits := child.evaluate(known, cmp, errs)
+		results = append(results, v)
+		if v == yes {
+			hits = append(hits, childHits...)
+		}
+	}
+
+	v := no
+	switch n.Operator {
+	case AND:
+		if len(results) > 0 {
+			v = yes
+		}
+		for _, r := range results {
+			if r < v {
+				v = r
+			}
+		}
+	default:
+		for _, r := range results {
+			if r > v {
+				v = r
+			}
+		}
+	}
+	if n.Negate {
+		return yes - v, nil
+	}
+	if v != yes {
+		return v, nil
+	}
+	return yes, hits
+}
+
+// matchedPlatforms returns the known platforms the match applies to, and
+// appends to errs an error for each platform it could not be evaluated against.
+func (m Match) matchedPlatforms(known []common.WellFormedName, cmp matching.VersionComparator, errs *[]error) []common.WellFormedName {
+	var result []common.WellFormedName
+	for _, k := range known {
+		ok, err := matching.IsApplicable(m.WFN, k, m.Range, cmp)
+		if err != nil {
+			*errs = append(*errs, errors.Wrapf(err, "Failed to evaluate %s against %s", m.Criteria, naming.BindToFS(k)))
+			continue
+		}
+		if ok {
+			result = append(result, k)
+		}
+	}
+	return result
+}
+
+func newMatch(vulnerable bool, criteria, id string, r matching.VersionRange) (Match, error) {
+	wfn, err := naming.UnbindFS(criteria)
+	if err != nil {
+		return Match{}, errors.Wrapf(err, "Failed to unbind %s", criteria)
+	}
+	if err = r.Validate(); err != nil {
+		return Match{}, errors.Wrapf(err, "Invalid version range of %s", criteria)
+	}
+	return Match{
+		Vulnerable:      vulnerable,
+		Criteria:        criteria,
+		WFN:             wfn,
+		Range:           r,
+		MatchCriteriaID: id,
+	}, nil
+}
+
+func newOperator(op string) (Operator, error) {
+	switch o := Operator(strings.ToUpper(op)); o {
+	case "", OR:
+		return OR, nil
+	case AND:
+		return AND, nil
+	}
+	return "", errors.Errorf("unknown operator: %s", op)
+}
@@ -0,0 +1,296 @@
+package nvd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/knqyf263/go-cpe/common"
+	"github.com/knqyf263/go-cpe/matching"
+	"github.com/knqyf263/go-cpe/naming"
+	"github.com/knqyf263/go-cpe/version"
+	"github.com/pkg/errors"
+)
+
+const configurations11 = `{
+  "CVE_data_version": "4.0",
+  "nodes": [{
+    "operator": "AND",
+    "children": [{
+      "operator": "OR",
+      "cpe_match": [{
+        "vulnerable": true,
+        "cpe23Uri": "cpe:2.3:a:adobe:acrobat_reader:*:*:*:*:*:*:*:*",
+        "versionStartIncluding": "9.0",
+        "versionEndExcluding": "9.4.1"
+      }]
+    }, {
+      "operator": "OR",
+      "cpe_match": [{
+        "vulnerable": false,
+        "cpe23Uri": "cpe:2.3:o:microsoft:windows:-:*:*:*:*:*:*:*"
+      }, {
+        "vulnerable": false,
+        "cpe23Uri": "cpe:2.3:o:apple:mac_os_x:-:*:*:*:*:*:*:*"
+      }]
+    }]
+  }, {
+    "operator": "OR",
+    "cpe_match": [{
+      "vulnerable": true,
+      "cpe23Uri": "cpe:2.3:a:adobe:acrobat:9.0:*:*:*:*:*:*:*"
+    }]
+  }]
+}`
+
+const configurations20 = `[{
+  "operator": "AND",
+  "nodes": [{
+    "operator": "OR",
+    "negate": false,
+    "cpeMatch": [{
+      "vulnerable": true,
+      "criteria": "cpe:2.3:a:adobe:acrobat_reader:*:*:*:*:*:*:*:*",
+      "versionStartIncluding": "9.0",
+      "versionEndExcluding": "9.4.1",
+      "matchCriteriaId": "A1B2C3D4-0000-0000-0000-000000000000"
+    }]
+  }, {
+    "operator": "OR",
+    "negate": true,
+    "cpeMatch": [{
+      "vulnerable": false,
+      "criteria": "cpe:2.3:o:apple:mac_os_x:-:*:*:*:*:*:*:*",
+      "matchCriteriaId": "A1B2C3D4-0000-0000-0000-000000000001"
+    }]
+  }]
+}]`
+
+// configurationsNegated excludes the platforms running mac_os_x before 10.0
+const configurationsNegated = `[{
+  "operator": "AND",
+  "nodes": [{
+    "operator": "OR",
+    "cpeMatch": [{
+      "vulnerable": true,
+      "criteria": "cpe:2.3:a:adobe:acrobat_reader:*:*:*:*:*:*:*:*",
+      "versionEndExcluding": "9.4.1"
+    }]
+  }, {
+    "operator": "OR",
+    "negate": true,
+    "cpeMatch": [{
+      "vulnerable": false,
+      "criteria": "cpe:2.3:o:apple:mac_os_x:*:*:*:*:*:*:*:*",
+      "versionEndExcluding": "10.0"
+    }]
+  }]
+}]`
+
+func mustUnbindFS(t *testing.T, fs string) common.WellFormedName {
+	wfn, err := naming.UnbindFS(fs)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s, FS: %s", err, fs)
+	}
+	return wfn
+}
+
+func TestParseConfigurations(t *testing.T) {
+	nodes, err := ParseConfigurations([]byte(configurations11))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(nodes) != 2 || nodes[0].Operator != AND || len(nodes[0].Children) != 2 || nodes[1].Operator != OR {
+		t.Fatalf("1.1 nodes: got %v", nodes)
+	}
+	m := nodes[0].Children[0].Matches[0]
+	if !m.Vulnerable || m.Range.StartIncluding != "9.0" || m.Range.EndExcluding != "9.4.1" ||
+		m.WFN.Get(common.AttributeProduct) != "acrobat_reader" {
+		t.Errorf("1.1 match: got %v", m)
+	}
+
+	nodes, err = ParseConfigurations([]byte(configurations20))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(nodes) != 1 || nodes[0].Operator != AND || len(nodes[0].Children) != 2 || !nodes[0].Children[1].Negate {
+		t.Fatalf("2.0 nodes: got %v", nodes)
+	}
+	if id := nodes[0].Children[0].Matches[0].MatchCriteriaID; id != "A1B2C3D4-0000-0000-0000-000000000000" {
+		t.Errorf("2.0 matchCriteriaId: got %s", id)
+	}
+}
+
+func TestParseConfigurationsError(t *testing.T) {
+	vectors := []string{
+		`{"nodes": [`,
+		`{"nodes": [{"operator": "XOR"}]}`,
+		`{"nodes": [{"operator": "OR", "cpe_match": [{"cpe23Uri": "cpe:/a:foo"}]}]}`,
+		`[{"nodes": [{"operator": "OR", "cpeMatch": [{"criteria": "cpe:2.3:a:foo:bar:*:*:*:*:*:*:*:*",
+			"versionStartIncluding": "1", "versionStartExcluding": "2"}]}]}]`,
+	}
+
+	for i, v := range vectors {
+		if _, err := ParseConfigurations([]byte(v)); err == nil {
+			t.Errorf("test %d, expected error", i)
+		}
+	}
+}
+
+var errBadVersion = errors.New("Bad version")
+
+// badComparator compares versions as the generic scheme does, but fails on "bad"
+var badComparator = version.ComparatorFunc(func(a, b string) (int, error) {
+	if a == "bad" || b == "bad" {
+		return 0, errBadVersion
+	}
+	return version.Generic.Compare(a, b)
+})
+
+func TestEvaluate(t *testing.T) {
+	nodes11, err := ParseConfigurations([]byte(configurations11))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	nodes20, err := ParseConfigurations([]byte(configurations20))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	nodesNegated, err := ParseConfigurations([]byte(configurationsNegated))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	vectors := []struct {
+		nodes      []Node
+		known      []string
+		cmp        matching.VersionComparator
+		vulnerable bool
+		hits       []string
+		errors     int
+	}{{
+		nodes: nodes11,
+		known: []string{
+			"cpe:2.3:a:adobe:acrobat_reader:9.3:*:*:*:*:*:*:*",
+			"cpe:2.3:o:microsoft:windows:-:*:*:*:*:*:*:*",
+		},
+		vulnerable: true,
+		hits:       []string{"cpe:2.3:a:adobe:acrobat_reader:*:*:*:*:*:*:*:*"},
+	}, {
+		nodes: nodes11,
+		known: []string{
+			"cpe:2.3:a:adobe:acrobat_reader:9.4.1:*:*:*:*:*:*:*",
+			"cpe:2.3:o:microsoft:windows:-:*:*:*:*:*:*:*",
+		},
+		vulnerable: false,
+	}, {
+		nodes: nodes11,
+		known: []string{
+			"cpe:2.3:a:adobe:acrobat_reader:9.3:*:*:*:*:*:*:*",
+			"cpe:2.3:o:linux:linux_kernel:5.0:*:*:*:*:*:*:*",
+		},
+		vulnerable: false,
+	}, {
+		nodes:      nodes11,
+		known:      []string{"cpe:2.3:a:adobe:acrobat:9.0:*:*:*:*:*:*:*"},
+		vulnerable: true,
+		hits:       []string{"cpe:2.3:a:adobe:acrobat:9.0:*:*:*:*:*:*:*"},
+	}, {
+		nodes:      nodes20,
+		known:      []string{"cpe:2.3:a:adobe:acrobat_reader:9.3:*:*:*:*:*:*:*"},
+		vulnerable: true,
+		hits:       []string{"cpe:2.3:a:adobe:acrobat_reader:*:*:*:*:*:*:*:*"},
+	}, {
+		nodes: nodes20,
+		known: []string{
+			"cpe:2.3:a:adobe:acrobat_reader:9.3:*:*:*:*:*:*:*",
+			"cpe:2.3:o:apple:mac_os_x:-:*:*:*:*:*:*:*",
+		},
+		vulnerable: false,
+	}, {
+		// a version that cannot be compared only fails its own platform
+		nodes: nodes11,
+		known: []string{
+			"cpe:2.3:a:adobe:acrobat_reader:bad:*:*:*:*:*:*:*",
+			"cpe:2.3:a:adobe:acrobat_reader:9.3:*:*:*:*:*:*:*",
+			"cpe:2.3:o:microsoft:windows:-:*:*:*:*:*:*:*",
+		},
+		cmp:        badComparator,
+		vulnerable: true,
+		hits:       []string{"cpe:2.3:a:adobe:acrobat_reader:*:*:*:*:*:*:*:*"},
+		errors:     1,
+	}, {
+		nodes: nodes11,
+		known: []string{
+			"cpe:2.3:a:adobe:acrobat_reader:bad:*:*:*:*:*:*:*",
+			"cpe:2.3:o:microsoft:windows:-:*:*:*:*:*:*:*",
+		},
+		cmp:        badComparator,
+		vulnerable: false,
+		errors:     1,
+	}, {
+		nodes: nodesNegated,
+		known: []string{
+			"cpe:2.3:a:adobe:acrobat_reader:9.3:*:*:*:*:*:*:*",
+			"cpe:2.3:o:apple:mac_os_x:10.5:*:*:*:*:*:*:*",
+		},
+		cmp:        badComparator,
+		vulnerable: true,
+		hits:       []string{"cpe:2.3:a:adobe:acrobat_reader:*:*:*:*:*:*:*:*"},
+	}, {
+		nodes: nodesNegated,
+		known: []string{
+			"cpe:2.3:a:adobe:acrobat_reader:9.3:*:*:*:*:*:*:*",
+			"cpe:2.3:o:apple:mac_os_x:9.2:*:*:*:*:*:*:*",
+		},
+		cmp:        badComparator,
+		vulnerable: false,
+	}, {
+		// a negated match that cannot be evaluated does not fail open
+		nodes: nodesNegated,
+		known: []string{
+			"cpe:2.3:a:adobe:acrobat_reader:9.3:*:*:*:*:*:*:*",
+			"cpe:2.3:o:apple:mac_os_x:bad:*:*:*:*:*:*:*",
+		},
+		cmp:        badComparator,
+		vulnerable: false,
+		errors:     1,
+	}, {
+		// nor does it when another platform decides the match
+		nodes: nodesNegated,
+		known: []string{
+			"cpe:2.3:a:adobe:acrobat_reader:9.3:*:*:*:*:*:*:*",
+			"cpe:2.3:o:apple:mac_os_x:bad:*:*:*:*:*:*:*",
+			"cpe:2.3:o:apple:mac_os_x:9.2:*:*:*:*:*:*:*",
+		},
+		cmp:        badComparator,
+		vulnerable: false,
+		errors:     1,
+	},
+	}
+
+	for i, v := range vectors {
+		var known []common.WellFormedName
+		for _, fs := range v.known {
+			known = append(known, mustUnbindFS(t, fs))
+		}
+		result := Evaluate(v.nodes, known, v.cmp)
+		if len(result.Errors) != v.errors {
+			t.Errorf("test %d, Errors: got %v, want %d", i, result.Errors, v.errors)
+		}
+		for _, err := range result.Errors {
+			if errors.Cause(err) != errBadVersion {
+				t.Errorf("test %d, Error: got %v, want %v", i, err, errBadVersion)
+			}
+		}
+		if result.Vulnerable != v.vulnerable {
+			t.Errorf("test %d, Vulnerable: got %v, want %v", i, result.Vulnerable, v.vulnerable)
+		}
+		var hits []string
+		for _, h := range result.Hits {
+			hits = append(hits, h.Match.Criteria)
+		}
+		if !reflect.DeepEqual(hits, v.hits) {
+			t.Errorf("test %d, Hits: got %v, want %v", i, hits, v.hits)
+		}
+	}
+}
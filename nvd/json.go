@@ -0,0 +1,141 @@
+package nvd
+
+import (
+	"github.com/knqyf263/go-cpe/matching"
+)
+
+// Configurations11 is the "configurations" object of a CVE JSON 1.1 item.
+type Configurations11 struct {
+	DataVersion string   `json:"CVE_data_version"`
+	Nodes       []Node11 `json:"nodes"`
+}
+
+// Node11 is a node of the CVE JSON 1.1 format.
+type Node11 struct {
+	Operator string       `json:"operator"`
+	Negate   bool         `json:"negate"`
+	Children []Node11     `json:"children"`
+	CPEMatch []CPEMatch11 `json:"cpe_match"`
+}
+
+// CPEMatch11 is a cpe_match of the CVE JSON 1.1 format.
+type CPEMatch11 struct {
+	Vulnerable            bool   `json:"vulnerable"`
+	Cpe23URI              string `json:"cpe23Uri"`
+	VersionStartIncluding string `json:"versionStartIncluding,omitempty"`
+	VersionStartExcluding string `json:"versionStartExcluding,omitempty"`
+	VersionEndIncluding   string `json:"versionEndIncluding,omitempty"`
+	VersionEndExcluding   string `json:"versionEndExcluding,omitempty"`
+}
+
+// Configurations20 is the "configurations" array of a CVE API 2.0 vulnerability.
+type Configurations20 []Configuration20
+
+// Configuration20 is a configuration of the CVE API 2.0.
+type Configuration20 struct {
+	Operator string   `json:"operator,omitempty"`
+	Negate   bool     `json:"negate,omitempty"`
+	Nodes    []Node20 `json:"nodes"`
+}
+
+// Node20 is a node of the CVE API 2.0.
+type Node20 struct {
+	Operator string       `json:"operator"`
+	Negate   bool         `json:"negate"`
+	CPEMatch []CPEMatch20 `json:"cpeMatch"`
+}
+
+// CPEMatch20 is a cpeMatch of the CVE API 2.0.
+type CPEMatch20 struct {
+	Vulnerable            bool   `json:"vulnerable"`
+	Criteria              string `json:"criteria"`
+	MatchCriteriaID       string `json:"matchCriteriaId"`
+	VersionStartIncluding string `json:"versionStartIncluding,omitempty"`
+	VersionStartExcluding string `json:"versionStartExcluding,omitempty"`
+	VersionEndIncluding   string `json:"versionEndIncluding,omitempty"`
+	VersionEndExcluding   string `json:"versionEndExcluding,omitempty"`
+}
+
+// Convert converts the configurations to evaluable root Nodes.
+func (c Configurations11) Convert() ([]Node, error) {
+	var nodes []Node
+	for _, n := range c.Nodes {
+		node, err := n.node()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+func (n Node11) node() (node Node, err error) {
+	if node.Operator, err = newOperator(n.Operator); err != nil {
+		return Node{}, err
+	}
+	node.Negate = n.Negate
+	for _, m := range n.CPEMatch {
+		r := matching.VersionRange{
+			StartIncluding: m.VersionStartIncluding,
+			StartExcluding: m.VersionStartExcluding,
+			EndIncluding:   m.VersionEndIncluding,
+			EndExcluding:   m.VersionEndExcluding,
+		}
+		match, err := newMatch(m.Vulnerable, m.Cpe23URI, "", r)
+		if err != nil {
+			return Node{}, err
+		}
+		node.Matches = append(node.Matches, match)
+	}
+	for _, child := range n.Children {
+		c, err := child.node()
+		if err != nil {
+			return Node{}, err
+		}
+		node.Children = append(node.Children, c)
+	}
+	return node, nil
+}
+
+// Convert converts the configurations to evaluable root Nodes,
+// one per configuration, combining its nodes with its operator.
+func (c Configurations20) Convert() ([]Node, error) {
+	var nodes []Node
+	for _, conf := range c {
+		op, err := newOperator(conf.Operator)
+		if err != nil {
+			return nil, err
+		}
+		root := Node{Operator: op, Negate: conf.Negate}
+		for _, n := range conf.Nodes {
+			node, err := n.node()
+			if err != nil {
+				return nil, err
+			}
+			root.Children = append(root.Children, node)
+		}
+		nodes = append(nodes, root)
+	}
+	return nodes, nil
+}
+
+func (n Node20) node() (node Node, err error) {
+	if node.Operator, err = newOperator(n.Operator); err != nil {
+		return Node{}, err
+	}
+	node.Negate = n.Negate
+	for _, m := range n.CPEMatch {
+		r := matching.VersionRange{
+			StartIncluding: m.VersionStartIncluding,
+			StartExcluding: m.VersionStartExcluding,
+			EndIncluding:   m.VersionEndIncluding,
+			EndExcluding:   m.VersionEndExcluding,
+		}
+		match, err := newMatch(m.Vulnerable, m.Criteria, m.MatchCriteriaID, r)
+		if err != nil {
+			return Node{}, err
+		}
+		node.Matches = append(node.Matches, match)
+	}
+	return node, nil
+}
@@ -0,0 +1,296 @@
+package store
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+
+	"github.com/knqyf263/go-cpe/common"
+	"github.com/pkg/errors"
+)
+
+// An index file holds a Store, so that it is loaded without unbinding and
+// indexing the names again.  Integers are little-endian:
+//
+//	magic     [8]byte "GOCPEIDX"
+//	version   uint32  FormatVersion
+//	count     uint32  number of names
+//	sections  uint32  number of sections
+//	reserved  uint32  zero
+//	directory offset and length of each section as two uint64s
+//	sections
+//	checksum  uint32  CRC-32 (Castagnoli) of everything before it
+//
+// The first two sections hold the names: count+1 uint32 offsets of the name
+// records, then the records.  A record holds the eleven attributes in the order
+// of common.Attributes, each as a kind byte followed, for a string, by its
+// length as an unsigned varint and its bytes.  Seven sections per indexed
+// attribute follow, as returned by attributeIndex.sections.
+const (
+	// FormatVersion is the version of the index files written by WriteTo
+	FormatVersion = 1
+
+	magic       = "GOCPEIDX"
+	headerSize  = 24
+	dirEntry    = 16
+	trailerSize = 4
+)
+
+// numSections is the number of sections of an index file
+var numSections = 2 + 7*len(indexedAttributes)
+
+var (
+	// ErrInvalidFormat is returned when data is not a valid index file
+	ErrInvalidFormat = errors.New("Invalid index file")
+	// ErrUnsupportedVersion is returned when an index file has a version that cannot be read
+	ErrUnsupportedVersion = errors.New("Unsupported index file version")
+	// ErrChecksum is returned when an index file does not match its checksum
+	ErrChecksum = errors.New("Index file checksum mismatch")
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// recordAttributes holds the attributes in the order of a name record
+var recordAttributes = common.Attributes()
+
+// kinds of the attribute values in a name record
+const (
+	kindAbsent byte = iota
+	kindANY
+	kindNA
+	kindString
+)
+
+// appendRecord appends the record of a name.  Values that are neither
+// strings nor logical values are stored as absent.
+func appendRecord(b []byte, wfn common.WellFormedName) []byte {
+	for _, attr := range recordAttributes {
+		switch v := wfn[attr].(type) {
+		case common.LogicalValue:
+			if v.IsNA() {
+				b = append(b, kindNA)
+			} else {
+				b = append(b, kindANY)
+			}
+		case string:
+			b = append(b, kindString)
+			var n [binary.MaxVarintLen64]byte
+			b = append(b, n[:binary.PutUvarint(n[:], uint64(len(v)))]...)
+			b = append(b, v...)
+		default:
+			b = append(b, kindAbsent)
+		}
+	}
+	return b
+}
+
+// decodeRecord decodes a name record into wfn.  If the record is invalid, wfn
+// holds the attributes decoded before the error.
+func decodeRecord(b []byte, wfn common.WellFormedName) error {
+	for _, attr := range recordAttributes {
+		if len(b) == 0 {
+			return errors.Wrap(ErrInvalidFormat, "Truncated name record")
+		}
+		kind := b[0]
+		b = b[1:]
+		var v interface{}
+		switch kind {
+		case kindAbsent:
+			continue
+		case kindANY:
+			v = common.LogicalValue{Any: true}
+		case kindNA:
+			v = common.LogicalValue{Na: true}
+		case kindString:
+			n, size := binary.Uvarint(b)
+			if size <= 0 || n > uint64(len(b)-size) {
+				return errors.Wrap(ErrInvalidFormat, "Truncated name record")
+			}
+			b = b[size:]
+			v = string(b[:n])
+			b = b[n:]
+		default:
+			return errors.Wrapf(ErrInvalidFormat, "Unknown kind %d in name record", kind)
+		}
+		wfn[attr] = v
+	}
+	if len(b) != 0 {
+		return errors.Wrap(ErrInvalidFormat, "Trailing bytes in name record")
+	}
+	return nil
+}
+
+func (s *Store) record(id int) []byte {
+	start := binary.LittleEndian.Uint32(s.offsets[4*id:])
+	end := binary.LittleEndian.Uint32(s.offsets[4*id+4:])
+	return s.records[start:end]
+}
+
+// sections returns the sections of the index file of the Store
+func (s *Store) sections() [][]byte {
+	offsets, records := s.offsets, s.records
+	if offsets == nil {
+		offsets = appendUint32(nil, 0)
+		for _, wfn := range s.names {
+			records = appendRecord(records, wfn)
+			offsets = appendUint32(offsets, uint32(len(records)))
+		}
+	}
+	sections := [][]byte{offsets, records}
+	for _, idx := range s.indexes {
+		sections = append(sections, idx.sections()...)
+	}
+	return sections
+}
+
+// WriteTo writes the Store as an index file, which Load and Open read.
+// The output only depends on the names, so a Store written twice gives
+// the same bytes.
+// @param w Writer
+// @return number of bytes written
+func (s *Store) WriteTo(w io.Writer) (int64, error) {
+	sections := s.sections()
+	head := make([]byte, 0, headerSize+dirEntry*len(sections))
+	head = append(head, magic...)
+	head = appendUint32(head, FormatVersion)
+	head = appendUint32(head, uint32(s.count))
+	head = appendUint32(head, uint32(len(sections)))
+	head = appendUint32(head, 0)
+	offset := uint64(headerSize + dirEntry*len(sections))
+	for _, section := range sections {
+		var e [dirEntry]byte
+		binary.LittleEndian.PutUint64(e[:], offset)
+		binary.LittleEndian.PutUint64(e[8:], uint64(len(section)))
+		head = append(head, e[:]...)
+		offset += uint64(len(section))
+	}
+
+	crc := crc32.New(castagnoli)
+	mw := io.MultiWriter(w, crc)
+	var written int64
+	for _, b := range append([][]byte{head}, sections...) {
+		n, err := mw.Write(b)
+		written += int64(n)
+		if err != nil {
+			return written, errors.Wrap(err, "Failed to write index file")
+		}
+	}
+	n, err := w.Write(appendUint32(nil, crc.Sum32()))
+	written += int64(n)
+	if err != nil {
+		return written, errors.Wrap(err, "Failed to write index file")
+	}
+	return written, nil
+}
+
+// Load reads a Store from an index file held in memory.  The names and
+// the index are used in place, without decoding them, so data must not be
+// modified while the Store is in use.
+// @param data index file
+// @return Store, or an error if data is not a valid index file of a supported version
+func Load(data []byte) (*Store, error) {
+	if len(data) < headerSize+trailerSize || string(data[:len(magic)]) != magic {
+		return nil, errors.Wrap(ErrInvalidFormat, "Missing index file header")
+	}
+	if v := binary.LittleEndian.Uint32(data[8:]); v != FormatVersion {
+		return nil, errors.Wrapf(ErrUnsupportedVersion, "Version %d", v)
+	}
+	body := data[:len(data)-trailerSize]
+	if crc32.Checksum(body, castagnoli) != binary.LittleEndian.Uint32(data[len(body):]) {
+		return nil, ErrChecksum
+	}
+
+	count := binary.LittleEndian.Uint32(body[12:])
+	n := binary.LittleEndian.Uint32(body[16:])
+	if int(n) != numSections || uint64(len(body)) < uint64(headerSize)+uint64(n)*dirEntry || count > 1<<31-1 {
+		return nil, errors.Wrap(ErrInvalidFormat, "Invalid index file header")
+	}
+	sections := make([][]byte, n)
+	for i := range sections {
+		e := body[headerSize+dirEntry*i:]
+		offset := binary.LittleEndian.Uint64(e)
+		length := binary.LittleEndian.Uint64(e[8:])
+		if offset > uint64(len(body)) || length > uint64(len(body))-offset {
+			return nil, errors.Wrapf(ErrInvalidFormat, "Section %d out of bounds", i)
+		}
+		sections[i] = body[offset : offset+length]
+	}
+
+	s := &Store{count: int(count), offsets: sections[0], records: sections[1]}
+	if err := s.validateRecords(); err != nil {
+		return nil, err
+	}
+	for i := range indexedAttributes {
+		sec := sections[2+7*i : 2+7*(i+1)]
+		if err := validateIndex(sec, count); err != nil {
+			return nil, errors.Wrapf(err, "Index of %s", indexedAttributes[i])
+		}
+		s.indexes = append(s.indexes, newIndexFromSections(sec[0], sec[1], sec[2], sec[3], sec[4], sec[5], sec[6]))
+	}
+	return s, nil
+}
+
+// validateRecords checks the offsets of the name records of a loaded Store.
+// The records themselves are only decoded by Name.
+func (s *Store) validateRecords() error {
+	if len(s.offsets) != 4*(s.count+1) {
+		return errors.Wrap(ErrInvalidFormat, "Invalid name offsets")
+	}
+	prev := uint32(0)
+	for i := 0; i <= s.count; i++ {
+		offset := binary.LittleEndian.Uint32(s.offsets[4*i:])
+		if offset < prev || int64(offset) > int64(len(s.records)) || (i == 0 && offset != 0) {
+			return errors.Wrap(ErrInvalidFormat, "Invalid name offsets")
+		}
+		prev = offset
+	}
+	return nil
+}
+
+// validateIndex checks the sections of an attribute index, so that
+// no offset read while querying it is out of bounds.
+func validateIndex(sections [][]byte, count uint32) error {
+	header, keys, ids := sections[0], sections[1], sections[2]
+	if len(header) != 24 || len(ids)%4 != 0 {
+		return errors.Wrap(ErrInvalidFormat, "Invalid index header")
+	}
+	for i := 0; i < len(ids); i += 4 {
+		if binary.LittleEndian.Uint32(ids[i:]) >= count {
+			return errors.Wrap(ErrInvalidFormat, "Name id out of range")
+		}
+	}
+	validRange := func(b []byte, start, end uint32, size uint32) bool {
+		return start <= end && int64(end) <= int64(len(b)) && (end-start)%size == 0
+	}
+	for i := 0; i < 6; i += 2 {
+		start, end := binary.LittleEndian.Uint32(header[4*i:]), binary.LittleEndian.Uint32(header[4*i+4:])
+		if !validRange(ids, start, end, 4) {
+			return errors.Wrap(ErrInvalidFormat, "Invalid index header")
+		}
+	}
+	for _, entries := range sections[3:] {
+		if len(entries)%entrySize != 0 {
+			return errors.Wrap(ErrInvalidFormat, "Invalid index table")
+		}
+		for i := 0; i < len(entries); i += entrySize {
+			e := entries[i:]
+			if !validRange(keys, binary.LittleEndian.Uint32(e), binary.LittleEndian.Uint32(e[4:]), 1) ||
+				!validRange(ids, binary.LittleEndian.Uint32(e[8:]), binary.LittleEndian.Uint32(e[12:]), 4) {
+				return errors.Wrap(ErrInvalidFormat, "Invalid index table entry")
+			}
+		}
+	}
+	return nil
+}
+
+// Close releases the index file mapped by Open.  The Store must not be used
+// afterwards, but the names it returned remain valid.  Close does nothing
+// for a Store built by New or Load.
+func (s *Store) Close() error {
+	if s.unmap == nil {
+		return nil
+	}
+	err := s.unmap()
+	s.unmap = nil
+	return err
+}
@@ -0,0 +1,297 @@
+package store
+
+import (
+	"bytes"
+	"encoding/binary"
+	"flag"
+	"hash/crc32"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/knqyf263/go-cpe/common"
+	"github.com/pkg/errors"
+)
+
+var update = flag.Bool("update", false, "update the golden index files in testdata")
+
+// reload writes a Store and loads it back.
+func reload(t testing.TB, s *Store) *Store {
+	var buf bytes.Buffer
+	n, err := s.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+	if n != int64(buf.Len()) {
+		t.Fatalf("WriteTo: got %d bytes, wrote %d", n, buf.Len())
+	}
+	loaded, err := Load(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return loaded
+}
+
+// assertSameStore checks that two stores hold the same names and answer
+// the same for each of them as a query.
+func assertSameStore(t *testing.T, expected, actual *Store) {
+	if actual.Len() != expected.Len() {
+		t.Fatalf("Len: got %d, want %d", actual.Len(), expected.Len())
+	}
+	for id := 0; id < expected.Len(); id++ {
+		query := expected.Name(id)
+		if !reflect.DeepEqual(actual.Name(id), query) {
+			t.Errorf("Name(%d): got %v, want %v", id, actual.Name(id), query)
+		}
+		if a, e := actual.Supersets(query), expected.Supersets(query); !reflect.DeepEqual(a, e) {
+			t.Errorf("Supersets(%v): got %v, want %v", query, a, e)
+		}
+		if a, e := actual.Subsets(query), expected.Subsets(query); !reflect.DeepEqual(a, e) {
+			t.Errorf("Subsets(%v): got %v, want %v", query, a, e)
+		}
+		if a, e := actual.Equal(query), expected.Equal(query); !reflect.DeepEqual(a, e) {
+			t.Errorf("Equal(%v): got %v, want %v", query, a, e)
+		}
+	}
+}
+
+func TestWriteToLoad(t *testing.T) {
+	s := newTestStore(t, storedNames)
+	loaded := reload(t, s)
+	assertSameStore(t, s, loaded)
+
+	// a loaded Store is written as it was read
+	var b1, b2 bytes.Buffer
+	if _, err := s.WriteTo(&b1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loaded.WriteTo(&b2); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b1.Bytes(), b2.Bytes()) {
+		t.Errorf("WriteTo of the loaded store differs")
+	}
+
+	empty := reload(t, New(nil))
+	if empty.Len() != 0 || empty.Supersets(loaded.Name(0)) != nil {
+		t.Errorf("empty store: got %d names", empty.Len())
+	}
+}
+
+func TestOpen(t *testing.T) {
+	s := newTestStore(t, storedNames)
+	var buf bytes.Buffer
+	if _, err := s.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "names.idx")
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opened, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	assertSameStore(t, s, opened)
+	name := opened.Name(0)
+	if err := opened.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+	if err := opened.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+	if !reflect.DeepEqual(name, s.Name(0)) {
+		t.Errorf("Name after Close: got %v, want %v", name, s.Name(0))
+	}
+
+	if _, err := Open(filepath.Join(t.TempDir(), "missing.idx")); err == nil {
+		t.Errorf("Open of a missing file: expected error")
+	}
+	if err := ioutil.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(path); errors.Cause(err) != ErrInvalidFormat {
+		t.Errorf("Open of an empty file: got %v, want %v", err, ErrInvalidFormat)
+	}
+}
+
+// TestCompatibility checks that index files written by earlier releases are
+// still read, and that the format written for a version does not change.
+// Run with -update after changing FormatVersion to add its golden file.
+func TestCompatibility(t *testing.T) {
+	s := newTestStore(t, storedNames)
+	var buf bytes.Buffer
+	if _, err := s.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	current := filepath.Join("testdata", "v1.idx")
+	if *update {
+		if err := ioutil.WriteFile(current, buf.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	vectors := []struct {
+		file  string
+		names []string
+	}{
+		{file: "v1.idx", names: storedNames},
+	}
+	for _, v := range vectors {
+		data, err := ioutil.ReadFile(filepath.Join("testdata", v.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := Load(data)
+		if err != nil {
+			t.Fatalf("%s: Load: %v", v.file, err)
+		}
+		assertSameStore(t, newTestStore(t, v.names), loaded)
+	}
+
+	golden, err := ioutil.ReadFile(current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), golden) {
+		t.Errorf("WriteTo differs from %s; increment FormatVersion when changing the format", current)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := newTestStore(t, storedNames).WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	valid := buf.Bytes()
+	// sectionOffset returns the offset of a section in valid
+	sectionOffset := func(i int) int {
+		return int(binary.LittleEndian.Uint64(valid[headerSize+dirEntry*i:]))
+	}
+	// checksum recomputes the checksum after a change
+	checksum := func(b []byte) {
+		body := b[:len(b)-trailerSize]
+		binary.LittleEndian.PutUint32(b[len(body):], crc32.Checksum(body, castagnoli))
+	}
+
+	vectors := []struct {
+		name    string
+		modify  func(b []byte) []byte
+		wantErr error
+	}{{
+		name:    "empty",
+		modify:  func(b []byte) []byte { return nil },
+		wantErr: ErrInvalidFormat,
+	}, {
+		name:    "magic",
+		modify:  func(b []byte) []byte { b[0] = 'X'; return b },
+		wantErr: ErrInvalidFormat,
+	}, {
+		name:    "version",
+		modify:  func(b []byte) []byte { b[8] = 2; checksum(b); return b },
+		wantErr: ErrUnsupportedVersion,
+	}, {
+		name:    "flipped byte",
+		modify:  func(b []byte) []byte { b[len(b)/2] ^= 1; return b },
+		wantErr: ErrChecksum,
+	}, {
+		name:    "truncated",
+		modify:  func(b []byte) []byte { return b[:len(b)-10] },
+		wantErr: ErrChecksum,
+	}, {
+		name:    "section count",
+		modify:  func(b []byte) []byte { b[16]--; checksum(b); return b },
+		wantErr: ErrInvalidFormat,
+	}, {
+		name: "section out of bounds",
+		modify: func(b []byte) []byte {
+			binary.LittleEndian.PutUint64(b[headerSize+8:], uint64(len(b)))
+			checksum(b)
+			return b
+		},
+		wantErr: ErrInvalidFormat,
+	}, {
+		name: "name offset",
+		modify: func(b []byte) []byte {
+			binary.LittleEndian.PutUint32(b[sectionOffset(0)+4:], 1<<30)
+			checksum(b)
+			return b
+		},
+		wantErr: ErrInvalidFormat,
+	}, {
+		name: "name id",
+		modify: func(b []byte) []byte {
+			binary.LittleEndian.PutUint32(b[sectionOffset(4):], uint32(len(storedNames)))
+			checksum(b)
+			return b
+		},
+		wantErr: ErrInvalidFormat,
+	}, {
+		name: "table entry",
+		modify: func(b []byte) []byte {
+			binary.LittleEndian.PutUint32(b[sectionOffset(5)+4:], 1<<30)
+			checksum(b)
+			return b
+		},
+		wantErr: ErrInvalidFormat,
+	},
+	}
+
+	for _, v := range vectors {
+		data := v.modify(append([]byte(nil), valid...))
+		_, err := Load(data)
+		if errors.Cause(err) != v.wantErr {
+			t.Errorf("%s: got error %v, want %v", v.name, err, v.wantErr)
+		}
+	}
+}
+
+func TestDecodeRecord(t *testing.T) {
+	wfn := common.WellFormedName{
+		"part":    "a",
+		"vendor":  common.LogicalValue{Any: true},
+		"product": common.LogicalValue{Na: true},
+		"version": `1\.0`,
+		"other":   "",
+	}
+	record := appendRecord(nil, wfn)
+	actual := common.WellFormedName{}
+	if err := decodeRecord(record, actual); err != nil {
+		t.Fatalf("decodeRecord: %v", err)
+	}
+	if !reflect.DeepEqual(actual, wfn) {
+		t.Errorf("decodeRecord: got %v, want %v", actual, wfn)
+	}
+
+	vectors := []struct {
+		name   string
+		record []byte
+	}{
+		{name: "truncated", record: record[:len(record)-1]},
+		{name: "truncated string", record: record[:3]},
+		{name: "trailing bytes", record: append(append([]byte(nil), record...), 0)},
+		{name: "unknown kind", record: append([]byte{9}, record[1:]...)},
+		{name: "string length", record: []byte{kindString, 0xff}},
+	}
+	for _, v := range vectors {
+		if err := decodeRecord(v.record, common.WellFormedName{}); errors.Cause(err) != ErrInvalidFormat {
+			t.Errorf("%s: got error %v, want %v", v.name, err, ErrInvalidFormat)
+		}
+	}
+}
+
+func BenchmarkLoad(b *testing.B) {
+	var buf bytes.Buffer
+	if _, err := newBenchmarkStore().WriteTo(&buf); err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(buf.Len()))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Load(buf.Bytes()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,452 @@
+package store
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+	"strings"
+
+	"github.com/knqyf263/go-cpe/common"
+)
+
+// maxLists is the number of postings above which the candidates for an
+// attribute are not listed, leaving the attribute to the verification.
+const maxLists = 1024
+
+// postings holds the ids of the names having a value, in ascending order,
+// as little-endian uint32s.
+type postings []byte
+
+func (p postings) len() int {
+	return len(p) / 4
+}
+
+func (p postings) at(i int) int32 {
+	return int32(binary.LittleEndian.Uint32(p[4*i:]))
+}
+
+// entrySize is the size of a table entry, which holds the start and end
+// offsets of its key in the keys of the attribute index, then those of its
+// postings in the ids of the attribute index, as little-endian uint32s.
+const entrySize = 16
+
+// table is a list of keys in ascending order with their postings, laid out
+// as in an index file so that a mapped file is used without decoding it.
+type table struct {
+	entries []byte
+	keys    []byte
+	ids     []byte
+}
+
+func (t table) len() int {
+	return len(t.entries) / entrySize
+}
+
+func (t table) key(i int) []byte {
+	e := t.entries[i*entrySize:]
+	return t.keys[binary.LittleEndian.Uint32(e):binary.LittleEndian.Uint32(e[4:])]
+}
+
+func (t table) postings(i int) postings {
+	e := t.entries[i*entrySize:]
+	return postings(t.ids[binary.LittleEndian.Uint32(e[8:]):binary.LittleEndian.Uint32(e[12:])])
+}
+
+// find returns the postings of a key, or nil if the table does not hold it
+func (t table) find(key string) postings {
+	i := sort.Search(t.len(), func(i int) bool { return string(t.key(i)) >= key })
+	if i < t.len() && string(t.key(i)) == key {
+		return t.postings(i)
+	}
+	return nil
+}
+
+// prefixRange returns the range of the entries whose keys start with prefix
+func (t table) prefixRange(prefix string) (int, int) {
+	lo := sort.Search(t.len(), func(i int) bool { return string(t.key(i)) >= prefix })
+	n := sort.Search(t.len()-lo, func(i int) bool {
+		k := t.key(lo + i)
+		return len(k) < len(prefix) || string(k[:len(prefix)]) != prefix
+	})
+	return lo, lo + n
+}
+
+// attributeIndex indexes the values of one attribute.  Strings are keyed by
+// their lowercase quoted form since matching is case insensitive.
+type attributeIndex struct {
+	any    postings
+	na     postings
+	others postings
+	// literals holds the strings that matching compares as targets, i.e.
+	// the strings without wildcards according to common.ContainsWildcards
+	literals table
+	// prefixes holds the patterns with trailing wildcards only, keyed by the
+	// literal part, e.g. 8\.0 for 8\.0*
+	prefixes table
+	// suffixes holds the patterns with leading wildcards only, keyed by the
+	// literal part, e.g. server for *server
+	suffixes table
+	// reversed holds the keys of literals reversed byte by byte, with the same postings
+	reversed table
+
+	// header holds the start and end offsets of any, na and others in ids
+	header []byte
+	keys   []byte
+	ids    []byte
+}
+
+// shape is enumeration for the positions of the wildcards in a string.
+type shape int
+
+const (
+	literal shape = iota
+	prefix
+	suffix
+	other
+)
+
+// classify finds the wildcards of a lowercase quoted string, as matching
+// does for a source value.
+// @param s string value
+// @return shape of s, and s without its leading and trailing wildcards
+func classify(s string) (shape, string) {
+	start, end := 0, len(s)
+	for start < end && (s[start] == '*' || s[start] == '?') {
+		start++
+	}
+	for end > start && (s[end-1] == '*' || s[end-1] == '?') && isUnquoted(s, end-1) {
+		end--
+	}
+	body := s[start:end]
+	switch {
+	case hasEmbeddedWildcards(body):
+		// embedded wildcards are not valid in a WFN, but must not be
+		// taken for literal characters
+		return other, ""
+	case start == 0 && end == len(s):
+		return literal, s
+	case start == end || (start > 0 && end < len(s)):
+		return other, body
+	case start > 0:
+		return suffix, body
+	}
+	return prefix, body
+}
+
+// hasEmbeddedWildcards returns true if s contains a wildcard not quoted by a backslash
+func hasEmbeddedWildcards(s string) bool {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '*', '?':
+			return true
+		}
+	}
+	return false
+}
+
+// isUnquoted returns true if the character at idx is not quoted by a backslash
+func isUnquoted(s string, idx int) bool {
+	n := 0
+	for idx > 0 && s[idx-1] == '\\' {
+		idx--
+		n++
+	}
+	return n%2 == 0
+}
+
+func newAttributeIndex(names []common.WellFormedName, attr string) *attributeIndex {
+	var any, na, others []int32
+	literals := map[string][]int32{}
+	prefixes := map[string][]int32{}
+	suffixes := map[string][]int32{}
+	for i, wfn := range names {
+		id := int32(i)
+		switch v := wfn.Get(attr).(type) {
+		case common.LogicalValue:
+			if v.IsNA() {
+				na = append(na, id)
+			} else {
+				any = append(any, id)
+			}
+		case string:
+			s := strings.ToLower(v)
+			if !common.ContainsWildcards(s) {
+				literals[s] = append(literals[s], id)
+			}
+			switch shape, key := classify(s); shape {
+			case prefix:
+				prefixes[key] = append(prefixes[key], id)
+			case suffix:
+				suffixes[key] = append(suffixes[key], id)
+			case other:
+				others = append(others, id)
+			}
+		}
+	}
+
+	b := &indexBuilder{}
+	var header []byte
+	for _, ids := range [][]int32{any, na, others} {
+		start, end := b.addPostings(ids)
+		header = appendUint32(header, start)
+		header = appendUint32(header, end)
+	}
+	offsets := map[string][2]uint32{}
+	literalEntries := b.addTable(literals, offsets)
+	prefixEntries := b.addTable(prefixes, nil)
+	suffixEntries := b.addTable(suffixes, nil)
+	reversedEntries := b.addReversed(offsets)
+	return newIndexFromSections(header, b.keys, b.ids, literalEntries, prefixEntries, suffixEntries, reversedEntries)
+}
+
+// newIndexFromSections builds an attributeIndex over its encoded sections,
+// which must have been checked by validateIndex if they were read from a file.
+func newIndexFromSections(header, keys, ids, literals, prefixes, suffixes, reversed []byte) *attributeIndex {
+	bound := func(i int) uint32 {
+		return binary.LittleEndian.Uint32(header[4*i:])
+	}
+	return &attributeIndex{
+		any:      postings(ids[bound(0):bound(1)]),
+		na:       postings(ids[bound(2):bound(3)]),
+		others:   postings(ids[bound(4):bound(5)]),
+		literals: table{entries: literals, keys: keys, ids: ids},
+		prefixes: table{entries: prefixes, keys: keys, ids: ids},
+		suffixes: table{entries: suffixes, keys: keys, ids: ids},
+		reversed: table{entries: reversed, keys: keys, ids: ids},
+		header:   header,
+		keys:     keys,
+		ids:      ids,
+	}
+}
+
+// sections returns the encoded sections of the index, in the order
+// newIndexFromSections takes them.
+func (idx *attributeIndex) sections() [][]byte {
+	return [][]byte{
+		idx.header, idx.keys, idx.ids,
+		idx.literals.entries, idx.prefixes.entries, idx.suffixes.entries, idx.reversed.entries,
+	}
+}
+
+// indexBuilder encodes the keys and postings of an attribute index.
+type indexBuilder struct {
+	keys []byte
+	ids  []byte
+}
+
+func (b *indexBuilder) addPostings(ids []int32) (uint32, uint32) {
+	start := uint32(len(b.ids))
+	for _, id := range ids {
+		b.ids = appendUint32(b.ids, uint32(id))
+	}
+	return start, uint32(len(b.ids))
+}
+
+func (b *indexBuilder) addKey(key string) (uint32, uint32) {
+	start := uint32(len(b.keys))
+	b.keys = append(b.keys, key...)
+	return start, uint32(len(b.keys))
+}
+
+// addTable encodes the entries of a table, and records the offsets of the
+// postings of each key in offsets unless it is nil.
+func (b *indexBuilder) addTable(m map[string][]int32, offsets map[string][2]uint32) []byte {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	entries := make([]byte, 0, len(keys)*entrySize)
+	for _, key := range keys {
+		keyStart, keyEnd := b.addKey(key)
+		idsStart, idsEnd := b.addPostings(m[key])
+		entries = appendEntry(entries, keyStart, keyEnd, idsStart, idsEnd)
+		if offsets != nil {
+			offsets[key] = [2]uint32{idsStart, idsEnd}
+		}
+	}
+	return entries
+}
+
+// addReversed encodes the entries of the reversed table, which shares
+// the postings of the literals.
+func (b *indexBuilder) addReversed(offsets map[string][2]uint32) []byte {
+	keys := make([]string, 0, len(offsets))
+	for key := range offsets {
+		keys = append(keys, reverse(key))
+	}
+	sort.Strings(keys)
+	entries := make([]byte, 0, len(keys)*entrySize)
+	for _, key := range keys {
+		keyStart, keyEnd := b.addKey(key)
+		o := offsets[reverse(key)]
+		entries = appendEntry(entries, keyStart, keyEnd, o[0], o[1])
+	}
+	return entries
+}
+
+func appendEntry(entries []byte, keyStart, keyEnd, idsStart, idsEnd uint32) []byte {
+	entries = appendUint32(entries, keyStart)
+	entries = appendUint32(entries, keyEnd)
+	entries = appendUint32(entries, idsStart)
+	return appendUint32(entries, idsEnd)
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
+
+// reverse reverses a string byte by byte, so that the suffixes of the
+// original become prefixes of the result
+func reverse(s string) string {
+	b := make([]byte, len(s))
+	for i := 0; i < len(s); i++ {
+		b[len(s)-1-i] = s[i]
+	}
+	return string(b)
+}
+
+// supersets returns the candidates for the names whose value may be a
+// superset of or equal to the query value.
+func (idx *attributeIndex) supersets(query interface{}) candidates {
+	var c candidates
+	switch v := query.(type) {
+	case common.LogicalValue:
+		// ANY is only matched by ANY, NA by ANY and NA
+		c.add(idx.any)
+		if v.IsNA() {
+			c.add(idx.na)
+		}
+	case string:
+		s := strings.ToLower(v)
+		if common.ContainsWildcards(s) {
+			// a target with wildcards matches nothing
+			return c
+		}
+		c.add(idx.any)
+		c.add(idx.literals.find(s))
+		for i := 0; i <= len(s); i++ {
+			c.add(idx.prefixes.find(s[:i]))
+			c.add(idx.suffixes.find(s[i:]))
+		}
+		c.add(idx.others)
+	}
+	return c
+}
+
+// subsets returns the candidates for the names whose value may be a
+// subset of or equal to the query value.
+func (idx *attributeIndex) subsets(query interface{}) candidates {
+	var c candidates
+	switch v := query.(type) {
+	case common.LogicalValue:
+		if v.IsNA() {
+			c.add(idx.na)
+		} else {
+			c.all = true
+		}
+	case string:
+		shape, key := classify(strings.ToLower(v))
+		switch shape {
+		case literal:
+			c.add(idx.literals.find(key))
+		case prefix, suffix:
+			t := idx.literals
+			if shape == suffix {
+				t, key = idx.reversed, reverse(key)
+			}
+			lo, hi := t.prefixRange(key)
+			if hi-lo > maxLists {
+				return candidates{all: true}
+			}
+			for i := lo; i < hi; i++ {
+				c.add(t.postings(i))
+			}
+		default:
+			body := []byte(key)
+			for i := 0; i < idx.literals.len(); i++ {
+				if bytes.Contains(idx.literals.key(i), body) {
+					c.add(idx.literals.postings(i))
+					if len(c.lists) > maxLists {
+						return candidates{all: true}
+					}
+				}
+			}
+		}
+	}
+	return c
+}
+
+// equal returns the candidates for the names whose value may be equal to the query value.
+func (idx *attributeIndex) equal(query interface{}) candidates {
+	var c candidates
+	switch v := query.(type) {
+	case common.LogicalValue:
+		if v.IsNA() {
+			c.add(idx.na)
+		} else {
+			c.add(idx.any)
+		}
+	case string:
+		c.add(idx.literals.find(strings.ToLower(v)))
+	}
+	return c
+}
+
+// candidates is the union of the postings that may hold the matches for one
+// attribute, or every name if all is true, e.g. for ANY or a pattern matching
+// too many values to be selective.
+type candidates struct {
+	all   bool
+	lists []postings
+}
+
+func (c *candidates) add(p postings) {
+	if len(p) > 0 {
+		c.lists = append(c.lists, p)
+	}
+}
+
+// size returns the number of ids in the lists, counting duplicates
+func (c candidates) size() int {
+	n := 0
+	for _, p := range c.lists {
+		n += p.len()
+	}
+	return n
+}
+
+// contains returns true if one of the lists holds id
+func (c candidates) contains(id int32) bool {
+	for _, p := range c.lists {
+		i := sort.Search(p.len(), func(i int) bool { return p.at(i) >= id })
+		if i < p.len() && p.at(i) == id {
+			return true
+		}
+	}
+	return false
+}
+
+// list returns the ids in the lists in ascending order, without duplicates
+func (c candidates) list() []int32 {
+	ids := make([]int32, 0, c.size())
+	for _, p := range c.lists {
+		for i := 0; i < p.len(); i++ {
+			ids = append(ids, p.at(i))
+		}
+	}
+	if len(c.lists) == 1 {
+		return ids
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	n := 0
+	for i, id := range ids {
+		if i == 0 || id != ids[n-1] {
+			ids[n] = id
+			n++
+		}
+	}
+	return ids[:n]
+}
@@ -0,0 +1,69 @@
+package store
+
+import "testing"
+
+func TestClassify(t *testing.T) {
+	vectors := []struct {
+		s     string
+		shape shape
+		key   string
+	}{
+		{s: "tomcat", shape: literal, key: "tomcat"},
+		{s: `8\.0\*`, shape: literal, key: `8\.0\*`},
+		{s: `8\.0*`, shape: prefix, key: `8\.0`},
+		{s: `8\.0??`, shape: prefix, key: `8\.0`},
+		{s: `8\.0\\*`, shape: prefix, key: `8\.0\\`},
+		{s: "*server", shape: suffix, key: "server"},
+		{s: "?server", shape: suffix, key: "server"},
+		{s: "*server*", shape: other, key: "server"},
+		{s: "?server?", shape: other, key: "server"},
+		{s: "??", shape: other, key: ""},
+		{s: "*", shape: other, key: ""},
+		{s: "foo*bar", shape: other, key: ""},
+		{s: `foo\*bar`, shape: literal, key: `foo\*bar`},
+		{s: `foo\\*bar`, shape: other, key: ""},
+		{s: "*foo*bar", shape: other, key: ""},
+	}
+	for i, v := range vectors {
+		shape, key := classify(v.s)
+		if shape != v.shape || key != v.key {
+			t.Errorf("test %d, classify(%q): got (%d, %q), want (%d, %q)", i, v.s, shape, key, v.shape, v.key)
+		}
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	c := candidates{}
+	c.add(newPostings(1, 4, 7))
+	c.add(nil)
+	c.add(newPostings(2, 4, 9))
+	if c.size() != 6 {
+		t.Errorf("size: got %d, want %d", c.size(), 6)
+	}
+	want := []int32{1, 2, 4, 7, 9}
+	got := c.list()
+	if len(got) != len(want) {
+		t.Fatalf("list: got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("list: got %v, want %v", got, want)
+		}
+	}
+	for _, id := range want {
+		if !c.contains(id) {
+			t.Errorf("contains(%d): got false, want true", id)
+		}
+	}
+	for _, id := range []int32{0, 3, 5, 10} {
+		if c.contains(id) {
+			t.Errorf("contains(%d): got true, want false", id)
+		}
+	}
+}
+
+func newPostings(ids ...int32) postings {
+	b := &indexBuilder{}
+	b.addPostings(ids)
+	return postings(b.ids)
+}
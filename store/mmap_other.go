@@ -0,0 +1,22 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package store
+
+import (
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+)
+
+// Open reads an index file written by WriteTo and loads it.  Memory mapping
+// is not supported on this platform, so the whole file is read.
+// @param path path of the index file
+// @return Store, or an error if the file cannot be read or is not a valid index file
+func Open(path string) (*Store, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to read index file")
+	}
+	return Load(data)
+}
@@ -0,0 +1,48 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package store
+
+import (
+	"os"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// Open maps an index file written by WriteTo into memory and loads it.
+// Only the pages touched by queries are read from disk after the checksum
+// is verified.  Call Close to release the mapping.
+// @param path path of the index file
+// @return Store, or an error if the file cannot be mapped or is not a valid index file
+func Open(path string) (*Store, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to open index file")
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to open index file")
+	}
+	size := fi.Size()
+	if size < headerSize+trailerSize {
+		return nil, errors.Wrap(ErrInvalidFormat, "Missing index file header")
+	}
+	if int64(int(size)) != size {
+		return nil, errors.Wrap(ErrInvalidFormat, "Index file too large")
+	}
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to map index file")
+	}
+	s, err := Load(data)
+	if err != nil {
+		syscall.Munmap(data)
+		return nil, err
+	}
+	s.unmap = func() error {
+		return syscall.Munmap(data)
+	}
+	return s, nil
+}
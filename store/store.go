@@ -0,0 +1,153 @@
+// Package store holds a large set of WFNs, such as dictionary entries, an inventory
+// or NVD criteria, and finds the stored names related to a WFN.
+// A Store is written to an index file by WriteTo, and Open maps it back into
+// memory to query it without indexing the names again.
+package store
+
+import (
+	"github.com/knqyf263/go-cpe/common"
+	"github.com/knqyf263/go-cpe/matching"
+)
+
+// indexedAttributes are the attributes whose values are indexed.
+// The other attributes are only checked when the candidates are verified.
+var indexedAttributes = []string{
+	common.AttributePart, common.AttributeVendor, common.AttributeProduct, common.AttributeVersion,
+}
+
+// Store is an immutable set of WFNs with an inverted index on part, vendor,
+// product and version.  Names are identified by their position in the slice
+// the Store was built from.  A Store is safe for concurrent use.
+type Store struct {
+	count int
+	// names holds the names given to New, or nil if the Store was loaded from an index file
+	names []common.WellFormedName
+	// offsets and records hold the encoded names if the Store was loaded from an index file
+	offsets []byte
+	records []byte
+	indexes []*attributeIndex
+	// unmap releases the mapped index file, if any
+	unmap func() error
+}
+
+// New builds a Store holding the given names.
+// @param names WFNs to store; the slice must not be modified afterwards
+// @return Store
+func New(names []common.WellFormedName) *Store {
+	s := &Store{count: len(names), names: names}
+	for _, attr := range indexedAttributes {
+		s.indexes = append(s.indexes, newAttributeIndex(names, attr))
+	}
+	return s
+}
+
+// Len returns the number of stored names
+func (s *Store) Len() int {
+	return s.count
+}
+
+// Name returns the stored name with the given id.
+// Names of a Store loaded from an index file are decoded on each call.
+// @param id position of the name in the slice given to New
+// @return WFN
+func (s *Store) Name(id int) common.WellFormedName {
+	if s.names != nil {
+		return s.names[id]
+	}
+	wfn := common.WellFormedName{}
+	// a record that does not decode, which the checksum makes unlikely,
+	// gives the attributes before the error
+	decodeRecord(s.record(id), wfn)
+	return wfn
+}
+
+// Supersets returns the stored names that are a superset of the query,
+// i.e. the names for which matching.IsSuperset(name, query) holds.
+// This finds the criteria that apply to a name.
+// @param query Target WFN
+// @return ids of the matching names, in ascending order
+func (s *Store) Supersets(query common.WellFormedName) []int {
+	var result []int
+	for _, id := range s.lookup(query, (*attributeIndex).supersets) {
+		if matching.IsSuperset(s.Name(int(id)), query) {
+			result = append(result, int(id))
+		}
+	}
+	return result
+}
+
+// Subsets returns the stored names that are a subset of the query,
+// i.e. the names for which matching.IsSuperset(query, name) holds.
+// This finds the names a pattern applies to.
+// @param query Source WFN
+// @return ids of the matching names, in ascending order
+func (s *Store) Subsets(query common.WellFormedName) []int {
+	m := matching.Compile(query)
+	var result []int
+	for _, id := range s.lookup(query, (*attributeIndex).subsets) {
+		if m.IsSuperset(s.Name(int(id))) {
+			result = append(result, int(id))
+		}
+	}
+	return result
+}
+
+// Equal returns the stored names equal to the query,
+// i.e. the names for which matching.IsEqual(name, query) holds.
+// @param query WFN
+// @return ids of the matching names, in ascending order
+func (s *Store) Equal(query common.WellFormedName) []int {
+	var result []int
+	for _, id := range s.lookup(query, (*attributeIndex).equal) {
+		if matching.IsEqual(s.Name(int(id)), query) {
+			result = append(result, int(id))
+		}
+	}
+	return result
+}
+
+// maxFilterLists is the number of postings up to which the candidates for an
+// attribute are used to filter the listed candidates; it is cheaper to
+// verify the names than to search more postings.
+const maxFilterLists = 16
+
+// lookup returns the candidates that find selects for every indexed attribute.
+// The attribute with the fewest candidates is listed, and the others are
+// only used to filter that list.
+func (s *Store) lookup(query common.WellFormedName, find func(*attributeIndex, interface{}) candidates) []int32 {
+	var found []candidates
+	smallest := -1
+	for i, idx := range s.indexes {
+		c := find(idx, query.Get(indexedAttributes[i]))
+		if c.all {
+			continue
+		}
+		found = append(found, c)
+		if smallest == -1 || c.size() < found[smallest].size() {
+			smallest = len(found) - 1
+		}
+	}
+	if smallest == -1 {
+		ids := make([]int32, s.count)
+		for i := range ids {
+			ids[i] = int32(i)
+		}
+		return ids
+	}
+
+	ids := found[smallest].list()
+	for i, c := range found {
+		if i == smallest || len(c.lists) > maxFilterLists {
+			continue
+		}
+		n := 0
+		for _, id := range ids {
+			if c.contains(id) {
+				ids[n] = id
+				n++
+			}
+		}
+		ids = ids[:n]
+	}
+	return ids
+}
@@ -0,0 +1,269 @@
+package store
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/knqyf263/go-cpe/common"
+	"github.com/knqyf263/go-cpe/matching"
+	"github.com/knqyf263/go-cpe/naming"
+)
+
+var storedNames = []string{
+	`cpe:2.3:a:apache:tomcat:8.0.0:rc1:*:*:*:*:*:*`,
+	`cpe:2.3:a:apache:tomcat:7.0:-:*:*:*:*:*:*`,
+	`cpe:2.3:a:apache:tomcat:7.0:*:*:*:*:*:*:*`,
+	`cpe:2.3:a:apache:tomcat:8.0.*:*:*:*:*:*:*:*`,
+	`cpe:2.3:a:apache:tomcat_server:*:*:*:*:*:*:*:*`,
+	`cpe:2.3:a:apache:*_server:-:*:*:*:*:*:*:*`,
+	`cpe:2.3:a:Apache:Tomcat:7.0:*:*:*:*:*:*:*`,
+	`cpe:2.3:o:microsoft:windows_7:-:sp1:*:*:*:*:*:*`,
+	`cpe:2.3:o:microsoft:*windows*:*:*:*:*:*:*:*:*`,
+	`cpe:2.3:h:cisco:asa_5505:-:*:*:*:*:*:*:*`,
+	`cpe:2.3:a:*:*:*:*:*:*:*:*:*:*`,
+}
+
+func newTestStore(t testing.TB, names []string) *Store {
+	var wfns []common.WellFormedName
+	for _, fs := range names {
+		wfn, err := naming.UnbindFS(fs)
+		if err != nil {
+			t.Fatalf("UnbindFS(%q): %v", fs, err)
+		}
+		wfns = append(wfns, wfn)
+	}
+	return New(wfns)
+}
+
+func TestStore(t *testing.T) {
+	s := newTestStore(t, storedNames)
+	if s.Len() != len(storedNames) {
+		t.Errorf("Len: got %d, want %d", s.Len(), len(storedNames))
+	}
+
+	vectors := []struct {
+		query     string
+		supersets []int
+		subsets   []int
+		equal     []int
+	}{{
+		query:     `cpe:2.3:a:apache:tomcat:8.0.1:*:*:*:*:*:*:*`,
+		supersets: []int{3, 10},
+	}, {
+		query:     `cpe:2.3:a:apache:tomcat:7.0:*:*:*:*:*:*:*`,
+		supersets: []int{2, 6, 10},
+		subsets:   []int{1, 2, 6},
+		equal:     []int{2},
+	}, {
+		query:     `cpe:2.3:a:apache:tomcat:8.0.*:*:*:*:*:*:*:*`,
+		supersets: nil,
+		subsets:   []int{0},
+	}, {
+		query:     `cpe:2.3:a:apache:mail_server:-:*:*:*:*:*:*:*`,
+		supersets: []int{5, 10},
+		subsets:   nil,
+	}, {
+		query:   `cpe:2.3:a:apache:*_server:*:*:*:*:*:*:*:*`,
+		subsets: []int{4},
+		equal:   nil,
+	}, {
+		query:     `cpe:2.3:o:microsoft:windows_7:-:sp1:*:*:*:*:*:*`,
+		supersets: []int{7, 8},
+		subsets:   []int{7},
+		equal:     []int{7},
+	}, {
+		query:   `cpe:2.3:o:*:*window*:*:*:*:*:*:*:*:*`,
+		subsets: []int{7},
+	}, {
+		query:   `cpe:2.3:h:*:*:-:*:*:*:*:*:*:*`,
+		subsets: []int{9},
+	}, {
+		query:     `cpe:2.3:a:*:*:*:*:*:*:*:*:*:*`,
+		supersets: []int{10},
+		subsets:   []int{0, 1, 2, 4, 6, 10},
+		equal:     []int{10},
+	},
+	}
+
+	for i, v := range vectors {
+		query, err := naming.UnbindFS(v.query)
+		if err != nil {
+			t.Fatalf("test %d, UnbindFS: %v", i, err)
+		}
+		if actual := s.Supersets(query); !reflect.DeepEqual(actual, v.supersets) {
+			t.Errorf("test %d, Supersets: got %v, want %v", i, actual, v.supersets)
+		}
+		if actual := s.Subsets(query); !reflect.DeepEqual(actual, v.subsets) {
+			t.Errorf("test %d, Subsets: got %v, want %v", i, actual, v.subsets)
+		}
+		if actual := s.Equal(query); !reflect.DeepEqual(actual, v.equal) {
+			t.Errorf("test %d, Equal: got %v, want %v", i, actual, v.equal)
+		}
+	}
+}
+
+func TestStoreQuoted(t *testing.T) {
+	names := []string{
+		`cpe:2.3:a:acme:foo:1\.0:*:*:*:*:*:*:*`,
+		`cpe:2.3:a:acme:foo:1\.0*:*:*:*:*:*:*:*`,
+		`cpe:2.3:a:acme:foo:1\.01:*:*:*:*:*:*:*`,
+		`cpe:2.3:a:acme:foo:1\.0?:*:*:*:*:*:*:*`,
+		`cpe:2.3:a:acme:foo:*\.0:*:*:*:*:*:*:*`,
+		`cpe:2.3:a:acme:foo:1\-0\.0:*:*:*:*:*:*:*`,
+	}
+	s := newTestStore(t, names)
+
+	vectors := []struct {
+		query     string
+		supersets []int
+		subsets   []int
+	}{{
+		query:     `cpe:2.3:a:acme:foo:1\.01:*:*:*:*:*:*:*`,
+		supersets: []int{1, 2, 3},
+		subsets:   []int{2},
+	}, {
+		query:     `cpe:2.3:a:acme:foo:1\.0x:*:*:*:*:*:*:*`,
+		supersets: []int{1, 3},
+	}, {
+		query:     `cpe:2.3:a:acme:foo:1\.0:*:*:*:*:*:*:*`,
+		supersets: []int{0, 1, 3, 4},
+		subsets:   []int{0},
+	}, {
+		query:   `cpe:2.3:a:acme:foo:1\.0*:*:*:*:*:*:*:*`,
+		subsets: []int{0, 2},
+	}, {
+		query:   `cpe:2.3:a:acme:foo:*\.0:*:*:*:*:*:*:*`,
+		subsets: []int{0, 5},
+	},
+	}
+
+	for i, v := range vectors {
+		query, err := naming.UnbindFS(v.query)
+		if err != nil {
+			t.Fatalf("test %d, UnbindFS: %v", i, err)
+		}
+		var supersets, subsets []int
+		for id := 0; id < s.Len(); id++ {
+			if matching.IsSuperset(s.Name(id), query) {
+				supersets = append(supersets, id)
+			}
+			if matching.IsSuperset(query, s.Name(id)) {
+				subsets = append(subsets, id)
+			}
+		}
+		if !reflect.DeepEqual(supersets, v.supersets) || !reflect.DeepEqual(subsets, v.subsets) {
+			t.Errorf("test %d, IsSuperset: got %v and %v, want %v and %v", i, supersets, subsets, v.supersets, v.subsets)
+		}
+		if actual := s.Supersets(query); !reflect.DeepEqual(actual, v.supersets) {
+			t.Errorf("test %d, Supersets: got %v, want %v", i, actual, v.supersets)
+		}
+		if actual := s.Subsets(query); !reflect.DeepEqual(actual, v.subsets) {
+			t.Errorf("test %d, Subsets: got %v, want %v", i, actual, v.subsets)
+		}
+	}
+}
+
+// randomNames generates names from a small set of values, so that
+// queries built the same way match many of them.
+func randomNames(r *rand.Rand, n int) []string {
+	values := []string{"*", "-", "foo", "Foo", "bar", "foo_bar", "foo*", "*bar", "*o*", "??", "f?", "1.0", `1\.*`, `1\.0`, `1\.0?`, `1\.01`, `1\.0\.1`, `\*x`, `x\\*`}
+	pick := func() string { return values[r.Intn(len(values))] }
+	var names []string
+	for i := 0; i < n; i++ {
+		part := []string{"a", "o", "h"}[r.Intn(3)]
+		names = append(names, fmt.Sprintf("cpe:2.3:%s:%s:%s:%s:%s:*:*:*:*:*:*", part, pick(), pick(), pick(), pick()))
+	}
+	return names
+}
+
+func TestStoreBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	names := randomNames(r, 1000)
+	s := newTestStore(t, names)
+	loaded := reload(t, s)
+	queries := append(randomNames(r, 100), names[:100]...)
+
+	for _, fs := range queries {
+		query, err := naming.UnbindFS(fs)
+		if err != nil {
+			t.Fatalf("UnbindFS(%q): %v", fs, err)
+		}
+		var supersets, subsets, equal []int
+		for id := 0; id < s.Len(); id++ {
+			if matching.IsSuperset(s.Name(id), query) {
+				supersets = append(supersets, id)
+			}
+			if matching.IsSuperset(query, s.Name(id)) {
+				subsets = append(subsets, id)
+			}
+			if matching.IsEqual(s.Name(id), query) {
+				equal = append(equal, id)
+			}
+		}
+		for _, s := range []*Store{s, loaded} {
+			if actual := s.Supersets(query); !reflect.DeepEqual(actual, supersets) {
+				t.Errorf("Supersets(%s): got %v, want %v", fs, actual, supersets)
+			}
+			if actual := s.Subsets(query); !reflect.DeepEqual(actual, subsets) {
+				t.Errorf("Subsets(%s): got %v, want %v", fs, actual, subsets)
+			}
+			if actual := s.Equal(query); !reflect.DeepEqual(actual, equal) {
+				t.Errorf("Equal(%s): got %v, want %v", fs, actual, equal)
+			}
+		}
+	}
+}
+
+// benchmarkNames generates n distinct names of 1000 vendors with 10 products
+// each, a tenth of them with version patterns.
+func benchmarkNames(n int) []common.WellFormedName {
+	var names []common.WellFormedName
+	for i := 0; i < n; i++ {
+		version := fmt.Sprintf(`%d\.%d\.%d`, i%7, i/7%10, i/70)
+		if i%10 == 0 {
+			version = fmt.Sprintf(`%d\.%d\.*`, i%7, i/7%10)
+		}
+		names = append(names, common.WellFormedName{
+			"part":    "a",
+			"vendor":  fmt.Sprintf("vendor%d", i%1000),
+			"product": fmt.Sprintf("product%d", i/1000%10),
+			"version": version,
+		})
+	}
+	return names
+}
+
+var (
+	benchmarkOnce  sync.Once
+	benchmarkStore *Store
+)
+
+func newBenchmarkStore() *Store {
+	benchmarkOnce.Do(func() {
+		benchmarkStore = New(benchmarkNames(1000000))
+	})
+	return benchmarkStore
+}
+
+func BenchmarkSupersets(b *testing.B) {
+	s := newBenchmarkStore()
+	query := common.WellFormedName{"part": "a", "vendor": "vendor42", "product": "product3", "version": `0\.6\.3`}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Supersets(query)
+	}
+}
+
+func BenchmarkSubsets(b *testing.B) {
+	s := newBenchmarkStore()
+	query := common.WellFormedName{"part": "a", "vendor": "vendor42", "product": "product3", "version": `0\.*`}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Subsets(query)
+	}
+}
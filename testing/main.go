@@ -1,32 +1,14 @@
 package main
 
 import (
-	"bytes"
-	"compress/gzip"
-	"encoding/xml"
 	"fmt"
 	"html/template"
-	"io/ioutil"
 	"math/rand"
 	"net/http"
 	"os"
-)
-
-// CpeList is CPE list
-type CpeList struct {
-	CpeItems []CpeItem `xml:"cpe-item"`
-}
-
-// CpeItem has CPE information
-type CpeItem struct {
-	Name      string    `xml:"name,attr"`
-	Cpe23Item Cpe23Item `xml:"cpe23-item"`
-}
 
-// Cpe23Item has CPE 2.3 information
-type Cpe23Item struct {
-	Name string `xml:"name,attr"`
-}
+	"github.com/knqyf263/go-cpe/dictionary"
+)
 
 // Pair has fs and uri
 type Pair struct {
@@ -41,32 +23,27 @@ func main() {
 		fmt.Printf("HTTP error. errs: %s, url: %s", err, url)
 		return
 	}
-
-	body, _ := ioutil.ReadAll(resp.Body)
 	defer resp.Body.Close()
 
-	b := bytes.NewBufferString(string(body))
-	reader, err := gzip.NewReader(b)
-	defer reader.Close()
+	d, err := dictionary.NewDecoder(resp.Body)
 	if err != nil {
 		fmt.Printf("Failed to decompress NVD feedfile. url: %s, err: %s", url, err)
 		return
 	}
-	bytes, err := ioutil.ReadAll(reader)
-	if err != nil {
-		fmt.Printf("Failed to Read NVD feedfile. url: %s, err: %s", url, err)
-		return
+	defer d.Close()
+
+	var uriList, fsList []string
+	for d.Next() {
+		uriList = append(uriList, d.Item().Name)
+		fsList = append(fsList, d.Item().FS)
 	}
-	cpeList := CpeList{}
-	if err = xml.Unmarshal(bytes, &cpeList); err != nil {
-		fmt.Printf("Failed to unmarshal. url: %s, err: %s", url, err)
+	if err = d.Err(); err != nil {
+		fmt.Printf("Failed to decode NVD feedfile. url: %s, err: %s", url, err)
 		return
 	}
-
-	var uriList, fsList []string
-	for _, cpeItem := range cpeList.CpeItems {
-		uriList = append(uriList, cpeItem.Name)
-		fsList = append(fsList, cpeItem.Cpe23Item.Name)
+	if n := d.Skipped(); n > 0 {
+		fmt.Printf("Failed to decode %d items of NVD feedfile. url: %s, last err: %s", n, url, d.SkipErr())
+		return
 	}
 	shuffle(fsList)
 
@@ -77,7 +54,7 @@ func main() {
 			FS:  fsList[i],
 		})
 	}
-	fmt.Printf("%d data...\n", len(cpeList.CpeItems))
+	fmt.Printf("%d data...\n", len(uriList))
 
 	fmt.Println("Generating test code...")
 	t := template.Must(template.ParseFiles("dictionary_test.tmpl"))
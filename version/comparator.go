@@ -0,0 +1,150 @@
+// Package version provides version comparison schemes for the ecosystems
+// CPE names come from. Every Comparator satisfies matching.VersionComparator.
+package version
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrInvalidVersion is returned when a version cannot be parsed by a scheme
+	ErrInvalidVersion = errors.New("Invalid version")
+	// ErrUnknownScheme is returned when a scheme name is not known
+	ErrUnknownScheme = errors.New("Unknown version scheme")
+)
+
+// Comparator compares two version strings.
+// Compare returns a negative number if a < b, zero if a == b
+// and a positive number if a > b.
+type Comparator interface {
+	Compare(a, b string) (int, error)
+}
+
+// ComparatorFunc is an adapter to allow the use of ordinary functions as Comparator.
+type ComparatorFunc func(a, b string) (int, error)
+
+// Compare calls f(a, b)
+func (f ComparatorFunc) Compare(a, b string) (int, error) {
+	return f(a, b)
+}
+
+// Scheme is enumeration for version comparison schemes.
+type Scheme string
+
+const (
+	// SchemeGeneric : NVD-style dotted/alpha versions
+	SchemeGeneric Scheme = "generic"
+	// SchemeSemver : Semantic Versioning 2.0.0
+	SchemeSemver Scheme = "semver"
+	// SchemeDebian : dpkg epoch:upstream-revision
+	SchemeDebian Scheme = "debian"
+	// SchemeRPM : rpmvercmp epoch:version-release
+	SchemeRPM Scheme = "rpm"
+	// SchemeMaven : Maven ComparableVersion
+	SchemeMaven Scheme = "maven"
+)
+
+var (
+	// Generic compares NVD-style dotted/alpha versions
+	Generic Comparator = ComparatorFunc(compareGeneric)
+	// Semver compares Semantic Versioning 2.0.0 versions
+	Semver Comparator = ComparatorFunc(compareSemver)
+	// Debian compares Debian package versions as dpkg does
+	Debian Comparator = ComparatorFunc(compareDebian)
+	// RPM compares RPM package versions as rpmvercmp does
+	RPM Comparator = ComparatorFunc(compareRPM)
+	// Maven compares Maven artifact versions as ComparableVersion does
+	Maven Comparator = ComparatorFunc(compareMaven)
+
+	ecosystems = map[string]Scheme{
+		"generic":   SchemeGeneric,
+		"semver":    SchemeSemver,
+		"npm":       SchemeSemver,
+		"node.js":   SchemeSemver,
+		"nodejs":    SchemeSemver,
+		"go":        SchemeSemver,
+		"golang":    SchemeSemver,
+		"cargo":     SchemeSemver,
+		"rust":      SchemeSemver,
+		"debian":    SchemeDebian,
+		"deb":       SchemeDebian,
+		"ubuntu":    SchemeDebian,
+		"dpkg":      SchemeDebian,
+		"rpm":       SchemeRPM,
+		"redhat":    SchemeRPM,
+		"rhel":      SchemeRPM,
+		"centos":    SchemeRPM,
+		"fedora":    SchemeRPM,
+		"suse":      SchemeRPM,
+		"maven":     SchemeMaven,
+		"java":      SchemeMaven,
+		"gradle":    SchemeMaven,
+		"jenkins":   SchemeMaven,
+		"sbt":       SchemeMaven,
+		"clojure":   SchemeMaven,
+		"leiningen": SchemeMaven,
+	}
+)
+
+// NewComparator returns the Comparator for a scheme
+// @param scheme Scheme name
+// @return Comparator, or ErrUnknownScheme
+func NewComparator(scheme Scheme) (Comparator, error) {
+	switch scheme {
+	case SchemeGeneric:
+		return Generic, nil
+	case SchemeSemver:
+		return Semver, nil
+	case SchemeDebian:
+		return Debian, nil
+	case SchemeRPM:
+		return RPM, nil
+	case SchemeMaven:
+		return Maven, nil
+	}
+	return nil, errors.Wrapf(ErrUnknownScheme, "scheme: %s", scheme)
+}
+
+// ForEcosystem returns the Comparator for an ecosystem name, such as a purl type,
+// a distribution name or a CPE target_sw value. Unknown ecosystems use Generic.
+// @param ecosystem case insensitive ecosystem name
+// @return Comparator
+func ForEcosystem(ecosystem string) Comparator {
+	if scheme, ok := ecosystems[strings.ToLower(ecosystem)]; ok {
+		c, _ := NewComparator(scheme)
+		return c
+	}
+	return Generic
+}
+
+// compareInts compares two strings of decimal digits of any length.
+func compareInts(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		return len(a) - len(b)
+	}
+	return strings.Compare(a, b)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isAlpha(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,61 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestNewComparator(t *testing.T) {
+	vectors := []struct {
+		scheme  Scheme
+		a       string
+		b       string
+		wantErr error
+	}{
+		{scheme: SchemeGeneric, a: "1.9", b: "1.10"},
+		{scheme: SchemeSemver, a: "1.0.0-rc.1", b: "1.0.0"},
+		{scheme: SchemeDebian, a: "1.0~rc1", b: "1.0"},
+		{scheme: SchemeRPM, a: "1.0~rc1", b: "1.0"},
+		{scheme: SchemeMaven, a: "1.0-SNAPSHOT", b: "1.0"},
+		{scheme: "pep440", wantErr: ErrUnknownScheme},
+	}
+
+	for i, v := range vectors {
+		c, err := NewComparator(v.scheme)
+		if errors.Cause(err) != v.wantErr {
+			t.Errorf("test %d, Error: got %v, want %v", i, errors.Cause(err), v.wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		if r, err := c.Compare(v.a, v.b); err != nil || r >= 0 {
+			t.Errorf("test %d, %s < %s: got %d, %v", i, v.a, v.b, r, err)
+		}
+	}
+}
+
+func TestForEcosystem(t *testing.T) {
+	vectors := []struct {
+		ecosystem string
+		a         string
+		b         string
+		expected  int
+	}{
+		{ecosystem: "Debian", a: "1.0~rc1", b: "1.0", expected: -1},
+		{ecosystem: "centos", a: "1.0~rc1", b: "1.0", expected: -1},
+		{ecosystem: "npm", a: "1.0.0-rc.1", b: "1.0.0", expected: -1},
+		{ecosystem: "maven", a: "1.0-rc1", b: "1.0", expected: -1},
+		{ecosystem: "unknown", a: "1.0~rc1", b: "1.0", expected: -1},
+	}
+
+	for i, v := range vectors {
+		c, err := ForEcosystem(v.ecosystem).Compare(v.a, v.b)
+		if err != nil {
+			t.Errorf("test %d, Unexpected error: %s", i, err)
+		}
+		if sign(c) != v.expected {
+			t.Errorf("test %d, %s vs %s: got %d, want %d", i, v.a, v.b, c, v.expected)
+		}
+	}
+}
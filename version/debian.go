@@ -0,0 +1,112 @@
+package version
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type debianVersion struct {
+	epoch    string
+	upstream string
+	revision string
+}
+
+// parseDebian splits a Debian version into [epoch:]upstream[-revision].
+func parseDebian(s string) (v debianVersion, err error) {
+	s = strings.TrimSpace(s)
+	v.epoch = "0"
+	if i := strings.IndexByte(s, ':'); i >= 0 {
+		if !isDigits(s[:i]) {
+			return debianVersion{}, errors.Wrapf(ErrInvalidVersion, "epoch must be numeric: %s", s)
+		}
+		v.epoch = s[:i]
+		s = s[i+1:]
+	}
+	if i := strings.LastIndexByte(s, '-'); i >= 0 {
+		v.revision = s[i+1:]
+		s = s[:i]
+	}
+	if s == "" {
+		return debianVersion{}, errors.Wrap(ErrInvalidVersion, "upstream version is empty")
+	}
+	v.upstream = s
+	return v, nil
+}
+
+// compareDebian compares two Debian package versions as dpkg does.
+func compareDebian(a, b string) (int, error) {
+	av, err := parseDebian(a)
+	if err != nil {
+		return 0, err
+	}
+	bv, err := parseDebian(b)
+	if err != nil {
+		return 0, err
+	}
+	if c := compareInts(av.epoch, bv.epoch); c != 0 {
+		return c, nil
+	}
+	if c := verrevcmp(av.upstream, bv.upstream); c != 0 {
+		return c, nil
+	}
+	return verrevcmp(av.revision, bv.revision), nil
+}
+
+// debianOrder returns the sort weight of a non-digit character:
+// the tilde sorts before everything, even the end of the string,
+// letters sort before all other characters.
+func debianOrder(s string, i int) int {
+	if i >= len(s) {
+		return 0
+	}
+	c := s[i]
+	switch {
+	case isDigit(c):
+		return 0
+	case isAlpha(c):
+		return int(c)
+	case c == '~':
+		return -1
+	}
+	return int(c) + 256
+}
+
+// verrevcmp is a port of the dpkg function of the same name.
+func verrevcmp(a, b string) int {
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		firstDiff := 0
+		for (i < len(a) && !isDigit(a[i])) || (j < len(b) && !isDigit(b[j])) {
+			ac, bc := debianOrder(a, i), debianOrder(b, j)
+			if ac != bc {
+				return ac - bc
+			}
+			i++
+			j++
+		}
+		for i < len(a) && a[i] == '0' {
+			i++
+		}
+		for j < len(b) && b[j] == '0' {
+			j++
+		}
+		for i < len(a) && isDigit(a[i]) && j < len(b) && isDigit(b[j]) {
+			if firstDiff == 0 {
+				firstDiff = int(a[i]) - int(b[j])
+			}
+			i++
+			j++
+		}
+		if i < len(a) && isDigit(a[i]) {
+			return 1
+		}
+		if j < len(b) && isDigit(b[j]) {
+			return -1
+		}
+		if firstDiff != 0 {
+			return firstDiff
+		}
+	}
+	return 0
+}
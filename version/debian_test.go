@@ -0,0 +1,39 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestCompareDebian(t *testing.T) {
+	vectors := []struct {
+		a        string
+		b        string
+		expected int
+		wantErr  error
+	}{
+		{a: "1.0", b: "1.0", expected: 0},
+		{a: "1.0-1", b: "1.0-2", expected: -1},
+		{a: "1:0.9", b: "1.0", expected: 1},
+		{a: "1.0~rc1", b: "1.0", expected: -1},
+		{a: "1.0~rc1", b: "1.0~rc1~beta", expected: 1},
+		{a: "1.0+dfsg", b: "1.0", expected: 1},
+		{a: "1.10", b: "1.9", expected: 1},
+		{a: "2.30-1ubuntu1", b: "2.30-1", expected: 1},
+		{a: "1.0a", b: "1.0+", expected: -1},
+		{a: "0001.0", b: "1.0", expected: 0},
+		{a: "x:1.0", b: "1.0", wantErr: ErrInvalidVersion},
+		{a: "1:", b: "1.0", wantErr: ErrInvalidVersion},
+	}
+
+	for i, v := range vectors {
+		c, err := compareDebian(v.a, v.b)
+		if errors.Cause(err) != v.wantErr {
+			t.Errorf("test %d, Error: got %v, want %v", i, errors.Cause(err), v.wantErr)
+		}
+		if err == nil && sign(c) != v.expected {
+			t.Errorf("test %d, %s vs %s: got %d, want %d", i, v.a, v.b, c, v.expected)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package version
+
+import (
+	"strings"
+)
+
+// preReleases ranks the qualifiers that make a pre-release of the version
+// before them; equal ranks are the same qualifier.
+var preReleases = map[string]int{
+	"~":     1,
+	"alpha": 2,
+	"a":     2,
+	"beta":  3,
+	"b":     3,
+	"pre":   4,
+	"rc":    4,
+}
+
+// compareGeneric compares NVD-style versions such as "1.10", "2.0b3" or "8.0_update_5".
+// Versions are split into runs of digits and runs of letters, and tildes; any
+// other character is a separator. Digit runs compare numerically and are greater
+// than letter runs, letter runs compare case insensitively. When all common runs
+// are equal the version with more runs is greater unless its extra runs are
+// zeros, so 1.0 = 1.0.0 < 1.0.1 < 1.0.1a.
+// Pre-release qualifiers (alpha, beta, pre, rc and ~) sort below anything else,
+// including the end of the version, so 2.0a1 < 2.0b1 < 2.0rc1 < 2.0 and
+// 1.0~rc1 < 1.0; the letters a and b are qualifiers only when a number follows.
+func compareGeneric(a, b string) (int, error) {
+	as := tokenize(strings.ToLower(a))
+	bs := tokenize(strings.ToLower(b))
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		aPre, bPre := preRelease(as, i), preRelease(bs, i)
+		aNum, bNum := isDigit(as[i][0]), isDigit(bs[i][0])
+		switch {
+		case aPre > 0 || bPre > 0:
+			if aPre == 0 {
+				return 1, nil
+			}
+			if bPre == 0 {
+				return -1, nil
+			}
+			if aPre != bPre {
+				return aPre - bPre, nil
+			}
+		case aNum && bNum:
+			if c := compareInts(as[i], bs[i]); c != 0 {
+				return c, nil
+			}
+		case aNum:
+			return 1, nil
+		case bNum:
+			return -1, nil
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c, nil
+			}
+		}
+	}
+	switch {
+	case len(as) > len(bs):
+		return compareRest(as, len(bs)), nil
+	case len(bs) > len(as):
+		return -compareRest(bs, len(as)), nil
+	}
+	return 0, nil
+}
+
+// compareRest compares a version with the shorter version made of its runs before i.
+func compareRest(tokens []string, i int) int {
+	for i < len(tokens) && isDigits(tokens[i]) && compareInts(tokens[i], "0") == 0 {
+		i++
+	}
+	switch {
+	case i == len(tokens):
+		return 0
+	case preRelease(tokens, i) > 0:
+		return -1
+	}
+	return 1
+}
+
+// preRelease returns the rank of tokens[i] if it is a pre-release qualifier, 0 otherwise.
+func preRelease(tokens []string, i int) int {
+	rank := preReleases[tokens[i]]
+	if len(tokens[i]) == 1 && isAlpha(tokens[i][0]) && (i+1 == len(tokens) || !isDigit(tokens[i+1][0])) {
+		// a lone letter is a suffix such as in 1.0.1a, not a qualifier
+		return 0
+	}
+	return rank
+}
+
+// tokenize splits s into runs of digits, runs of letters and tildes.
+func tokenize(s string) (tokens []string) {
+	start := -1
+	for i := 0; i <= len(s); i++ {
+		if start >= 0 && (i == len(s) || isDigit(s[i]) != isDigit(s[start]) || !(isDigit(s[i]) || isAlpha(s[i]))) {
+			tokens = append(tokens, s[start:i])
+			start = -1
+		}
+		if i < len(s) && s[i] == '~' {
+			tokens = append(tokens, "~")
+			continue
+		}
+		if start < 0 && i < len(s) && (isDigit(s[i]) || isAlpha(s[i])) {
+			start = i
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,71 @@
+package version
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompareGeneric(t *testing.T) {
+	vectors := []struct {
+		a        string
+		b        string
+		expected int
+	}{
+		{a: "1.10", b: "1.9", expected: 1},
+		{a: "1.0", b: "1.0", expected: 0},
+		{a: "1.0", b: "1.0.1", expected: -1},
+		{a: "1.0.1", b: "1.0.1a", expected: -1},
+		{a: "2.0b3", b: "2.0b10", expected: -1},
+		{a: "2.0B3", b: "2.0b3", expected: 0},
+		{a: "8.0_update_5", b: "8.0.update.5", expected: 0},
+		{a: "1.0.a", b: "1.0.1", expected: -1},
+		{a: "2.0rc1", b: "2.0", expected: -1},
+		{a: "2.0", b: "2.0rc1", expected: 1},
+		{a: "1.0~rc1", b: "1.0", expected: -1},
+		{a: "1.0~rc1", b: "1.0rc1", expected: -1},
+		{a: "2.0a1", b: "2.0b1", expected: -1},
+		{a: "2.0alpha1", b: "2.0a1", expected: 0},
+		{a: "2.0beta2", b: "2.0rc1", expected: -1},
+		{a: "2.0-pre", b: "2.0", expected: -1},
+		{a: "2.0rc1", b: "2.0.1", expected: -1},
+		{a: "2.0rc1", b: "2.0sp1", expected: -1},
+		{a: "2.0rc2", b: "2.0rc10", expected: -1},
+		{a: "1.0", b: "1.0.0", expected: 0},
+		{a: "2.0.0", b: "2.0", expected: 0},
+		{a: "2.0", b: "2.0.0.0", expected: 0},
+		{a: "2.0", b: "2.0.0.1", expected: -1},
+		{a: "2.0", b: "2.0.0a", expected: -1},
+		{a: "2.0", b: "2.0.0rc1", expected: 1},
+		{a: "2.0.0rc1", b: "2.0", expected: -1},
+	}
+
+	for i, v := range vectors {
+		c, err := compareGeneric(v.a, v.b)
+		if err != nil {
+			t.Errorf("test %d, Unexpected error: %s", i, err)
+		}
+		if sign(c) != v.expected {
+			t.Errorf("test %d, %s vs %s: got %d, want %d", i, v.a, v.b, c, v.expected)
+		}
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	vectors := []struct {
+		s        string
+		expected []string
+	}{
+		{s: "1.10", expected: []string{"1", "10"}},
+		{s: "2.0b3", expected: []string{"2", "0", "b", "3"}},
+		{s: "-rc..1-", expected: []string{"rc", "1"}},
+		{s: "1.0~rc1", expected: []string{"1", "0", "~", "rc", "1"}},
+		{s: "", expected: nil},
+	}
+
+	for i, v := range vectors {
+		actual := tokenize(v.s)
+		if !reflect.DeepEqual(actual, v.expected) {
+			t.Errorf("test %d, Result: %v, want %v", i, actual, v.expected)
+		}
+	}
+}
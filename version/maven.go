@@ -0,0 +1,261 @@
+package version
+
+import (
+	"strconv"
+	"strings"
+)
+
+// The items of a Maven version, a port of the item types of
+// org.apache.maven.artifact.versioning.ComparableVersion.
+type mavenItem interface {
+	// compare compares the item with another item, or with nil
+	// when the other version has no more items.
+	compare(other mavenItem) int
+	isNull() bool
+}
+
+type mavenIntItem string
+
+type mavenStringItem string
+
+type mavenListItem []mavenItem
+
+var (
+	mavenQualifiers = []string{"alpha", "beta", "milestone", "rc", "snapshot", "", "sp"}
+	mavenAliases    = map[string]string{"ga": "", "final": "", "release": "", "cr": "rc"}
+	// the index of "" in mavenQualifiers
+	mavenReleaseVersionIndex = strconv.Itoa(5)
+)
+
+func newMavenIntItem(s string) mavenIntItem {
+	s = strings.TrimLeft(s, "0")
+	if s == "" {
+		s = "0"
+	}
+	return mavenIntItem(s)
+}
+
+func (i mavenIntItem) isNull() bool {
+	return i == "0"
+}
+
+func (i mavenIntItem) compare(other mavenItem) int {
+	switch o := other.(type) {
+	case nil:
+		if i.isNull() {
+			return 0
+		}
+		return 1
+	case mavenIntItem:
+		return sign(compareInts(string(i), string(o)))
+	}
+	// 1.1 > 1-sp and 1.1 > 1-1
+	return 1
+}
+
+func newMavenStringItem(s string, followedByDigit bool) mavenStringItem {
+	if followedByDigit && len(s) == 1 {
+		// a1 = alpha-1, b1 = beta-1, m1 = milestone-1
+		switch s {
+		case "a":
+			s = "alpha"
+		case "b":
+			s = "beta"
+		case "m":
+			s = "milestone"
+		}
+	}
+	if alias, ok := mavenAliases[s]; ok {
+		s = alias
+	}
+	return mavenStringItem(s)
+}
+
+// comparableQualifier returns a string that sorts known qualifiers in
+// release order and unknown qualifiers after them, lexically.
+func comparableQualifier(q string) string {
+	for i, k := range mavenQualifiers {
+		if k == q {
+			return strconv.Itoa(i)
+		}
+	}
+	return strconv.Itoa(len(mavenQualifiers)) + "-" + q
+}
+
+func (s mavenStringItem) isNull() bool {
+	return s == ""
+}
+
+func (s mavenStringItem) compare(other mavenItem) int {
+	switch o := other.(type) {
+	case nil:
+		// 1-rc < 1, 1-ga > 1
+		return sign(strings.Compare(comparableQualifier(string(s)), mavenReleaseVersionIndex))
+	case mavenStringItem:
+		return sign(strings.Compare(comparableQualifier(string(s)), comparableQualifier(string(o))))
+	}
+	// 1.any < 1.1 and 1.any < 1-1
+	return -1
+}
+
+func (l mavenListItem) isNull() bool {
+	return len(l) == 0
+}
+
+func (l mavenListItem) compare(other mavenItem) int {
+	switch o := other.(type) {
+	case nil:
+		if len(l) == 0 {
+			return 0
+		}
+		return l[0].compare(nil)
+	case mavenIntItem:
+		// 1-1 < 1.0.x
+		return -1
+	case mavenStringItem:
+		// 1-1 > 1-sp
+		return 1
+	case mavenListItem:
+		for i := 0; i < len(l) || i < len(o); i++ {
+			var left, right mavenItem
+			if i < len(l) {
+				left = l[i]
+			}
+			if i < len(o) {
+				right = o[i]
+			}
+			var c int
+			if left == nil {
+				if right != nil {
+					c = -right.compare(nil)
+				}
+			} else {
+				c = left.compare(right)
+			}
+			if c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
+
+// normalize removes trailing null items, stopping at the first non-list item.
+func (l mavenListItem) normalize() mavenListItem {
+	for i := len(l) - 1; i >= 0; i-- {
+		if l[i].isNull() {
+			l = append(l[:i], l[i+1:]...)
+		} else if _, ok := l[i].(mavenListItem); !ok {
+			break
+		}
+	}
+	return l
+}
+
+// mavenListBuilder builds nested list items; lists are slices, so children
+// are attached to their parent when the parse is finished.
+type mavenListBuilder struct {
+	items    mavenListItem
+	parent   *mavenListBuilder
+	children []*mavenListBuilder
+}
+
+func (b *mavenListBuilder) add(item mavenItem) {
+	b.items = append(b.items, item)
+}
+
+func (b *mavenListBuilder) addList() *mavenListBuilder {
+	child := &mavenListBuilder{parent: b}
+	// placeholder, replaced by build
+	b.items = append(b.items, nil)
+	b.children = append(b.children, child)
+	return child
+}
+
+func (b *mavenListBuilder) build() mavenListItem {
+	c := 0
+	for i, item := range b.items {
+		if item == nil {
+			b.items[i] = b.children[c].build()
+			c++
+		}
+	}
+	return b.items.normalize()
+}
+
+func parseMavenItem(isDigit bool, s string) mavenItem {
+	if isDigit {
+		return newMavenIntItem(s)
+	}
+	return newMavenStringItem(s, false)
+}
+
+// parseMaven parses a version as ComparableVersion.parseVersion does.
+func parseMaven(version string) mavenListItem {
+	version = strings.ToLower(version)
+	root := &mavenListBuilder{}
+	list := root
+	digit := false
+	start := 0
+	for i := 0; i < len(version); i++ {
+		c := version[i]
+		switch {
+		case c == '.':
+			if i == start {
+				list.add(newMavenIntItem("0"))
+			} else {
+				list.add(parseMavenItem(digit, version[start:i]))
+			}
+			start = i + 1
+		case c == '-':
+			if i == start {
+				list.add(newMavenIntItem("0"))
+			} else {
+				list.add(parseMavenItem(digit, version[start:i]))
+			}
+			start = i + 1
+			list = list.addList()
+		case isDigit(c):
+			if !digit && i > start {
+				// 1.0.0.X1 < 1.0.0-X2: treat .X as -X for any string qualifier X
+				if len(list.items) != 0 {
+					list = list.addList()
+				}
+				list.add(newMavenStringItem(version[start:i], true))
+				start = i
+				list = list.addList()
+			}
+			digit = true
+		default:
+			if digit && i > start {
+				list.add(parseMavenItem(true, version[start:i]))
+				start = i
+				list = list.addList()
+			}
+			digit = false
+		}
+	}
+	if len(version) > start {
+		// 1.0.0.X1 < 1.0.0-X2: treat .X as -X for any string qualifier X
+		if !digit && len(list.items) != 0 {
+			list = list.addList()
+		}
+		list.add(parseMavenItem(digit, version[start:]))
+	}
+	return root.build()
+}
+
+// compareMaven compares two versions as Maven's ComparableVersion does.
+func compareMaven(a, b string) (int, error) {
+	return parseMaven(a).compare(parseMaven(b)), nil
+}
+
+func sign(i int) int {
+	if i < 0 {
+		return -1
+	}
+	if i > 0 {
+		return 1
+	}
+	return 0
+}
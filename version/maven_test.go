@@ -0,0 +1,56 @@
+package version
+
+import (
+	"testing"
+)
+
+func TestCompareMaven(t *testing.T) {
+	// ordered versions from Maven's ComparableVersionTest
+	vectors := [][]string{{
+		"1-alpha2snapshot", "1-alpha2", "1-alpha-123", "1-beta-2", "1-beta123", "1-m2", "1-m11", "1-rc",
+		"1-cr2", "1-rc123", "1-SNAPSHOT", "1", "1-sp", "1-sp2", "1-sp123", "1-abc", "1-def", "1-pom-1",
+		"1-1-snapshot", "1-1", "1-2", "1-123",
+	}, {
+		"2.0", "2.0.a", "2-1", "2.0.2", "2.0.123", "2.1.0", "2.1-a", "2.1b", "2.1-c", "2.1-1", "2.1.0.1",
+		"2.2", "2.123", "11.a2", "11.a11", "11.b2", "11.b11", "11.m2", "11.m11", "11", "11.a", "11b", "11c", "11m",
+	},
+	}
+
+	for i, versions := range vectors {
+		for j := 1; j < len(versions); j++ {
+			for k := 0; k < j; k++ {
+				if c, _ := compareMaven(versions[k], versions[j]); c >= 0 {
+					t.Errorf("test %d, %s < %s: got %d", i, versions[k], versions[j], c)
+				}
+				if c, _ := compareMaven(versions[j], versions[k]); c <= 0 {
+					t.Errorf("test %d, %s > %s: got %d", i, versions[j], versions[k], c)
+				}
+			}
+		}
+	}
+}
+
+func TestCompareMavenEqual(t *testing.T) {
+	vectors := []struct {
+		a string
+		b string
+	}{
+		{a: "1", b: "1.0.0"},
+		{a: "1", b: "1-0"},
+		{a: "1", b: "1-ga"},
+		{a: "1", b: "1-final"},
+		{a: "1", b: "1.release"},
+		{a: "1a", b: "1-a"},
+		{a: "1a1", b: "1-alpha-1"},
+		{a: "1b2", b: "1-beta-2"},
+		{a: "1m3", b: "1-milestone-3"},
+		{a: "1x", b: "1X"},
+		{a: "1-cr1", b: "1-rc1"},
+	}
+
+	for i, v := range vectors {
+		if c, _ := compareMaven(v.a, v.b); c != 0 {
+			t.Errorf("test %d, %s = %s: got %d", i, v.a, v.b, c)
+		}
+	}
+}
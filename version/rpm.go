@@ -0,0 +1,162 @@
+package version
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type rpmVersion struct {
+	epoch   string
+	version string
+	release string
+}
+
+// parseRPM splits an RPM version into [epoch:]version[-release].
+func parseRPM(s string) (v rpmVersion, err error) {
+	s = strings.TrimSpace(s)
+	v.epoch = "0"
+	if i := strings.IndexByte(s, ':'); i >= 0 {
+		if !isDigits(s[:i]) {
+			return rpmVersion{}, errors.Wrapf(ErrInvalidVersion, "epoch must be numeric: %s", s)
+		}
+		v.epoch = s[:i]
+		s = s[i+1:]
+	}
+	if i := strings.LastIndexByte(s, '-'); i >= 0 {
+		v.release = s[i+1:]
+		s = s[:i]
+	}
+	if s == "" {
+		return rpmVersion{}, errors.Wrap(ErrInvalidVersion, "version is empty")
+	}
+	v.version = s
+	return v, nil
+}
+
+// compareRPM compares two RPM package versions. The release is only compared
+// when both versions have one, as rpm does.
+func compareRPM(a, b string) (int, error) {
+	av, err := parseRPM(a)
+	if err != nil {
+		return 0, err
+	}
+	bv, err := parseRPM(b)
+	if err != nil {
+		return 0, err
+	}
+	if c := compareInts(av.epoch, bv.epoch); c != 0 {
+		return c, nil
+	}
+	if c := rpmvercmp(av.version, bv.version); c != 0 {
+		return c, nil
+	}
+	if av.release == "" || bv.release == "" {
+		return 0, nil
+	}
+	return rpmvercmp(av.release, bv.release), nil
+}
+
+func isRPMSeparator(c byte) bool {
+	return !isDigit(c) && !isAlpha(c) && c != '~' && c != '^'
+}
+
+// rpmvercmp is a port of the rpm function of the same name,
+// including the tilde (pre-release) and caret (post-release) operators.
+func rpmvercmp(a, b string) int {
+	if a == b {
+		return 0
+	}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		for i < len(a) && isRPMSeparator(a[i]) {
+			i++
+		}
+		for j < len(b) && isRPMSeparator(b[j]) {
+			j++
+		}
+
+		// handle the tilde separator, it sorts before everything else
+		if (i < len(a) && a[i] == '~') || (j < len(b) && b[j] == '~') {
+			if i >= len(a) || a[i] != '~' {
+				return 1
+			}
+			if j >= len(b) || b[j] != '~' {
+				return -1
+			}
+			i++
+			j++
+			continue
+		}
+
+		// handle caret separator, it sorts after the end of the string
+		// but before anything else
+		if (i < len(a) && a[i] == '^') || (j < len(b) && b[j] == '^') {
+			if i >= len(a) {
+				return -1
+			}
+			if j >= len(b) {
+				return 1
+			}
+			if a[i] != '^' {
+				return 1
+			}
+			if b[j] != '^' {
+				return -1
+			}
+			i++
+			j++
+			continue
+		}
+
+		if i >= len(a) || j >= len(b) {
+			break
+		}
+
+		si, sj := i, j
+		isnum := isDigit(a[i])
+		if isnum {
+			for i < len(a) && isDigit(a[i]) {
+				i++
+			}
+			for j < len(b) && isDigit(b[j]) {
+				j++
+			}
+		} else {
+			for i < len(a) && isAlpha(a[i]) {
+				i++
+			}
+			for j < len(b) && isAlpha(b[j]) {
+				j++
+			}
+		}
+
+		// segments of different types: numeric is newer
+		if sj == j {
+			if isnum {
+				return 1
+			}
+			return -1
+		}
+
+		var c int
+		if isnum {
+			c = compareInts(a[si:i], b[sj:j])
+		} else {
+			c = strings.Compare(a[si:i], b[sj:j])
+		}
+		if c != 0 {
+			if c < 0 {
+				return -1
+			}
+			return 1
+		}
+	}
+	if i >= len(a) && j >= len(b) {
+		return 0
+	}
+	if i < len(a) {
+		return 1
+	}
+	return -1
+}
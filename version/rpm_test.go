@@ -0,0 +1,48 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestCompareRPM(t *testing.T) {
+	// vectors from rpm's rpmvercmp.at
+	vectors := []struct {
+		a        string
+		b        string
+		expected int
+		wantErr  error
+	}{
+		{a: "1.0", b: "1.0", expected: 0},
+		{a: "1.0", b: "2.0", expected: -1},
+		{a: "2.0.1", b: "2.0", expected: 1},
+		{a: "2.0.1a", b: "2.0.1", expected: 1},
+		{a: "5.5p1", b: "5.5p10", expected: -1},
+		{a: "10xyz", b: "10.1xyz", expected: -1},
+		{a: "xyz10", b: "xyz10.1", expected: -1},
+		{a: "1.0aa", b: "1.0a", expected: 1},
+		{a: "2a", b: "2.0", expected: -1},
+		{a: "1.0", b: "1.fc4", expected: 1},
+		{a: "3.0.0_fc", b: "3.0.0.fc", expected: 0},
+		{a: "1.0~rc1", b: "1.0", expected: -1},
+		{a: "1.0~rc1~git123", b: "1.0~rc1", expected: -1},
+		{a: "1.0^", b: "1.0", expected: 1},
+		{a: "1.0^git1", b: "1.01", expected: -1},
+		{a: "1.0^git1~pre", b: "1.0^git1", expected: -1},
+		{a: "1:1.0-1", b: "2.0-1", expected: 1},
+		{a: "1.0-2", b: "1.0-10", expected: -1},
+		{a: "1.0", b: "1.0-10", expected: 0},
+		{a: "a:1.0", b: "1.0", wantErr: ErrInvalidVersion},
+	}
+
+	for i, v := range vectors {
+		c, err := compareRPM(v.a, v.b)
+		if errors.Cause(err) != v.wantErr {
+			t.Errorf("test %d, Error: got %v, want %v", i, errors.Cause(err), v.wantErr)
+		}
+		if err == nil && sign(c) != v.expected {
+			t.Errorf("test %d, %s vs %s: got %d, want %d", i, v.a, v.b, c, v.expected)
+		}
+	}
+}
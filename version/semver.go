@@ -0,0 +1,84 @@
+package version
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type semver struct {
+	core       [3]string
+	prerelease []string
+}
+
+// parseSemver parses a Semantic Versioning 2.0.0 version.
+// A leading "v" is accepted and missing minor or patch numbers default to 0,
+// since CPE versions such as "1.2" are common. Build metadata is ignored.
+func parseSemver(s string) (v semver, err error) {
+	s = strings.TrimPrefix(s, "v")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		v.prerelease = strings.Split(s[i+1:], ".")
+		for _, p := range v.prerelease {
+			if p == "" {
+				return semver{}, errors.Wrapf(ErrInvalidVersion, "empty pre-release identifier: %s", s)
+			}
+		}
+		s = s[:i]
+	}
+	core := strings.Split(s, ".")
+	if len(core) > 3 {
+		return semver{}, errors.Wrapf(ErrInvalidVersion, "too many version numbers: %s", s)
+	}
+	v.core = [3]string{"0", "0", "0"}
+	for i, c := range core {
+		if !isDigits(c) {
+			return semver{}, errors.Wrapf(ErrInvalidVersion, "version number must be numeric: %s", s)
+		}
+		v.core[i] = c
+	}
+	return v, nil
+}
+
+// compareSemver compares two versions by Semantic Versioning 2.0.0 precedence.
+func compareSemver(a, b string) (int, error) {
+	av, err := parseSemver(a)
+	if err != nil {
+		return 0, err
+	}
+	bv, err := parseSemver(b)
+	if err != nil {
+		return 0, err
+	}
+	for i := range av.core {
+		if c := compareInts(av.core[i], bv.core[i]); c != 0 {
+			return c, nil
+		}
+	}
+	// A version without pre-release has higher precedence.
+	if len(av.prerelease) == 0 || len(bv.prerelease) == 0 {
+		return len(bv.prerelease) - len(av.prerelease), nil
+	}
+	for i := 0; i < len(av.prerelease) && i < len(bv.prerelease); i++ {
+		ap, bp := av.prerelease[i], bv.prerelease[i]
+		aNum, bNum := isDigits(ap), isDigits(bp)
+		switch {
+		case aNum && bNum:
+			if c := compareInts(ap, bp); c != 0 {
+				return c, nil
+			}
+		case aNum:
+			// numeric identifiers have lower precedence.
+			return -1, nil
+		case bNum:
+			return 1, nil
+		default:
+			if c := strings.Compare(ap, bp); c != 0 {
+				return c, nil
+			}
+		}
+	}
+	return len(av.prerelease) - len(bv.prerelease), nil
+}
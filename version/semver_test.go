@@ -0,0 +1,51 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestCompareSemver(t *testing.T) {
+	// ordered versions from the Semantic Versioning 2.0.0 specification
+	versions := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11",
+		"1.0.0-rc.1", "1.0.0", "1.9.0", "1.10.0", "1.11.0", "2.0.0", "2.1.0", "2.1.1",
+	}
+	for j := 1; j < len(versions); j++ {
+		if c, err := compareSemver(versions[j-1], versions[j]); err != nil || c >= 0 {
+			t.Errorf("%s < %s: got %d, %v", versions[j-1], versions[j], c, err)
+		}
+	}
+}
+
+func TestCompareSemverEqual(t *testing.T) {
+	vectors := []struct {
+		a       string
+		b       string
+		wantErr error
+	}{{
+		a: "1.0.0", b: "v1.0.0",
+	}, {
+		a: "1.0.0+build.1", b: "1.0.0+build.2",
+	}, {
+		a: "1.2", b: "1.2.0",
+	}, {
+		a: "1.2.x", b: "1.2.0", wantErr: ErrInvalidVersion,
+	}, {
+		a: "1.0.0-", b: "1.0.0", wantErr: ErrInvalidVersion,
+	}, {
+		a: "1.2.3.4", b: "1.2.3", wantErr: ErrInvalidVersion,
+	},
+	}
+
+	for i, v := range vectors {
+		c, err := compareSemver(v.a, v.b)
+		if errors.Cause(err) != v.wantErr {
+			t.Errorf("test %d, Error: got %v, want %v", i, errors.Cause(err), v.wantErr)
+		}
+		if err == nil && c != 0 {
+			t.Errorf("test %d, %s = %s: got %d", i, v.a, v.b, c)
+		}
+	}
+}